@@ -0,0 +1,135 @@
+package libstandard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TagAlias is a comma-separated list of former keys of a field in the config-file, e.g. after a rename.
+// The value of the first alias key present is used if the file doesn't contain the key of the field itself.
+// Aliases are supported for all formats whose parser can decode into a map[string]interface{}, like yaml and json.
+const TagAlias = "alias"
+
+// usesAliases reports whether cfg is a pointer to a structure with alias tags
+func usesAliases(cfg interface{}) bool {
+	root := reflect.ValueOf(cfg)
+	return root.Kind() == reflect.Ptr && root.Elem().Kind() == reflect.Struct && hasAliases(root.Elem().Type())
+}
+
+// applyAliases decodes the content of the config-file with the parser selected for it into a map and
+// decodes the values of alias keys into the fields whose own key is missing
+func applyAliases(cfg interface{}, parser ParserFunc, content []byte, ext string) error {
+	var values map[string]interface{}
+	if err := parser(bytes.NewReader(content), &values); err != nil {
+		// parsers which only decode into structures, like the built-in ini-parser, don't support aliases
+		return nil
+	}
+
+	// the values of all formats except json are re-encoded as yaml, like decodeFileMap does
+	tag, unmarshal, marshal := "yaml", yaml.Unmarshal, yaml.Marshal
+	if ext == ".json" {
+		tag, unmarshal, marshal = "json", json.Unmarshal, json.Marshal
+	}
+
+	return decodeAliases(values, reflect.ValueOf(cfg).Elem(), tag, func(value interface{}, field reflect.Value) error {
+		raw, err := marshal(value)
+		if err != nil {
+			return err
+		}
+		return unmarshal(raw, field.Addr().Interface())
+	})
+}
+
+// decodeAliases decodes the values of the alias keys into the fields of the structure whose own key is missing
+// in values, the keys of the fields are taken from the given tag (e.g. "yaml" or "json") or their field-names
+func decodeAliases(values map[string]interface{}, s reflect.Value, tag string, decode func(interface{}, reflect.Value) error) error {
+	typeInfo := s.Type()
+	for idx := 0; idx < s.NumField(); idx++ {
+		fType := typeInfo.Field(idx)
+		field := s.Field(idx)
+
+		name, tagOpts, _ := strings.Cut(fType.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		// the fields of embedded structures are flattened by json without own name, by yaml only if inlined
+		inline := strings.Contains(","+tagOpts+",", ",inline,") || (tag == "json" && fType.Anonymous && name == "")
+		if inline && field.Kind() == reflect.Struct {
+			if err := decodeAliases(values, field, tag, decode); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.CanSet() {
+			continue
+		}
+
+		if name == "" {
+			name = fType.Name
+		}
+
+		value, ok := lookupKey(values, name, tag)
+		if !ok {
+			for _, alias := range strings.Split(fType.Tag.Get(TagAlias), DefaultSeparator) {
+				alias = strings.TrimSpace(alias)
+				if alias == "" {
+					continue
+				}
+
+				if value, ok = lookupKey(values, alias, tag); ok {
+					if err := decode(value, field); err != nil {
+						return fmt.Errorf("alias %q of field %q: %w", alias, fType.Name, err)
+					}
+					break
+				}
+			}
+		}
+
+		if nested, isMap := value.(map[string]interface{}); isMap && field.Kind() == reflect.Struct {
+			if err := decodeAliases(nested, field, tag, decode); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookupKey returns the value of the key, which is matched like the decoder of the format does:
+// case-sensitively for yaml and case-insensitively for json
+func lookupKey(values map[string]interface{}, key, tag string) (interface{}, bool) {
+	if value, ok := values[key]; ok || tag != "json" {
+		return value, ok
+	}
+
+	for k, value := range values {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// hasAliases reports whether a field of the structure or of its nested structures has an alias tag
+func hasAliases(typeInfo reflect.Type) bool {
+	for idx := 0; idx < typeInfo.NumField(); idx++ {
+		fType := typeInfo.Field(idx)
+		if fType.Tag.Get(TagAlias) != "" {
+			return true
+		}
+
+		if fType.Type.Kind() == reflect.Struct && hasAliases(fType.Type) {
+			return true
+		}
+	}
+
+	return false
+}
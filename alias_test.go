@@ -0,0 +1,196 @@
+package libstandard
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestReadFromFileWithAliases(t *testing.T) {
+	type database struct {
+		Host string `yaml:"host" json:"host" alias:"hostname,server"`
+		Port int    `yaml:"port" json:"port"`
+	}
+
+	type config struct {
+		ListenPort int      `yaml:"listen_port" json:"listen_port" alias:"port"`
+		Tags       []string `yaml:"tags" json:"tags" alias:"labels"`
+		Database   database `yaml:"database" json:"database" alias:"db"`
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"alias.yaml":  "port: 8080\nlabels: [a, b]\ndatabase:\n  hostname: db.local\n  port: 5432\n",
+		"alias.json":  `{"port": 8080, "labels": ["a", "b"], "database": {"server": "db.local", "port": 5432}}`,
+		"both.yaml":   "listen_port: 9090\nport: 8080\ntags: [new]\nlabels: [old]\ndatabase:\n  host: new.local\n  hostname: old.local\n",
+		"both.json":   `{"listen_port": 9090, "port": 8080, "tags": ["new"], "labels": ["old"], "database": {"host": "new.local", "hostname": "old.local"}}`,
+		"nested.yaml": "db:\n  hostname: db.local\n  port: 5432\n",
+		"section.yml": "services:\n  api:\n    port: 8080\n",
+		"case.yaml":   "Listen_Port: 9090\nport: 8080\n",
+		"case.json":   `{"Listen_Port": 9090, "port": 8080}`,
+		"invalid.yml": "port: abc\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		opts    ReadOptions
+		want    config
+		wantErr string
+	}{
+		{
+			name: "yaml alias only",
+			file: "alias.yaml",
+			want: config{ListenPort: 8080, Tags: []string{"a", "b"}, Database: database{Host: "db.local", Port: 5432}},
+		},
+		{
+			name: "json alias only",
+			file: "alias.json",
+			want: config{ListenPort: 8080, Tags: []string{"a", "b"}, Database: database{Host: "db.local", Port: 5432}},
+		},
+		{
+			name: "yaml new key wins",
+			file: "both.yaml",
+			want: config{ListenPort: 9090, Tags: []string{"new"}, Database: database{Host: "new.local"}},
+		},
+		{
+			name: "json new key wins",
+			file: "both.json",
+			want: config{ListenPort: 9090, Tags: []string{"new"}, Database: database{Host: "new.local"}},
+		},
+		{
+			name: "alias of nested structure",
+			file: "nested.yaml",
+			want: config{Database: database{Host: "db.local", Port: 5432}},
+		},
+		{
+			name: "alias in section",
+			file: "section.yml",
+			opts: ReadOptions{Section: "services.api"},
+			want: config{ListenPort: 8080},
+		},
+		{
+			name: "yaml keys are case-sensitive",
+			file: "case.yaml",
+			want: config{ListenPort: 8080},
+		},
+		{
+			name: "json keys are case-insensitive",
+			file: "case.json",
+			want: config{ListenPort: 9090},
+		},
+		{
+			name:    "invalid alias value",
+			file:    "invalid.yml",
+			wantErr: `alias "port" of field "ListenPort"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config
+			err := ReadWithOptions(&cfg, nil, filepath.Join(dir, tt.file), DefaultFileConfig{}, tt.opts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestReadFromFileWithAliasesAndParserOptions(t *testing.T) {
+	type Base struct {
+		Host string `yaml:"host" json:"host" alias:"hostname"`
+	}
+
+	type database struct {
+		Name string `yaml:"name" json:"name"`
+		User string `yaml:"user" json:"user" alias:"username"`
+	}
+
+	type config struct {
+		Base     `yaml:",inline"`
+		Port     int      `yaml:"port" json:"port" alias:"listen"`
+		Database database `yaml:"database" json:"database"`
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"docs.yaml":   "hostname: first.local\ndatabase:\n  username: admin\n---\nlisten: 8080\ndatabase:\n  name: app\n",
+		"docs.json":   `{"hostname": "embedded.local", "listen": 8080}`,
+		"docs.upper":  "HOSTNAME: custom.local\nLISTEN: 9090\n",
+		"section.yml": "api:\n  listen: 1\n---\napi:\n  hostname: second.local\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	defer func() {
+		parsersMu.Lock()
+		delete(parsers, ".upper")
+		parsersMu.Unlock()
+	}()
+
+	RegisterParser("upper", func(r io.Reader, cfg interface{}) error {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return yaml.Unmarshal(bytes.ToLower(content), cfg)
+	})
+
+	tests := []struct {
+		name string
+		file string
+		opts ReadOptions
+		want config
+	}{
+		{
+			name: "merged yaml documents",
+			file: "docs.yaml",
+			opts: ReadOptions{MergeYAMLDocuments: true},
+			want: config{Base: Base{Host: "first.local"}, Port: 8080, Database: database{Name: "app", User: "admin"}},
+		},
+		{
+			name: "first yaml document only",
+			file: "docs.yaml",
+			want: config{Base: Base{Host: "first.local"}, Database: database{User: "admin"}},
+		},
+		{
+			name: "json embedded structure",
+			file: "docs.json",
+			want: config{Base: Base{Host: "embedded.local"}, Port: 8080},
+		},
+		{
+			name: "registered parser",
+			file: "docs.upper",
+			want: config{Base: Base{Host: "custom.local"}, Port: 9090},
+		},
+		{
+			name: "merged yaml sections",
+			file: "section.yml",
+			opts: ReadOptions{MergeYAMLDocuments: true, Section: "api"},
+			want: config{Base: Base{Host: "second.local"}, Port: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config
+			err := ReadWithOptions(&cfg, nil, filepath.Join(dir, tt.file), DefaultFileConfig{}, tt.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
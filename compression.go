@@ -2,31 +2,160 @@ package libstandard
 
 import (
 	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
 
 	"github.com/andybalholm/brotli"
 )
 
+// ErrDictionaryUnsupported is returned when a custom brotli-dictionary is passed, as the
+// underlying brotli-implementation only supports its built-in static dictionary.
+var ErrDictionaryUnsupported = errors.New("custom brotli dictionaries are not supported")
+
 func Compress(data []byte) ([]byte, error) {
-	srcBuf := bytes.NewBuffer(data)
-	dstBuf := bytes.NewBuffer(make([]byte, 0))
-	writer := brotli.NewWriterLevel(dstBuf, 11)
-	_, err := srcBuf.WriteTo(writer)
-	if err != nil {
-		return nil, err
+	return compressLevel(data, 11)
+}
+
+// CompressWithDict compresses data with the given quality-level (0-11) using dict as shared dictionary.
+// An empty dict compresses without a custom dictionary, any other dict fails with ErrDictionaryUnsupported.
+func CompressWithDict(data, dict []byte, level int) ([]byte, error) {
+	if len(dict) > 0 {
+		return nil, ErrDictionaryUnsupported
 	}
 
-	err = writer.Close()
-	if err != nil {
+	return compressLevel(data, level)
+}
+
+// CompressAdaptive compresses data with a quality-level chosen by its size, see adaptiveLevel:
+// inputs below 4KiB use the best level 11, inputs below 1MiB use level 6 and larger inputs use level 4.
+func CompressAdaptive(data []byte) ([]byte, error) {
+	return compressLevel(data, adaptiveLevel(len(data)))
+}
+
+// adaptiveLevel returns the quality-level used by CompressAdaptive for an input of the given size
+func adaptiveLevel(size int) int {
+	switch {
+	case size < 4<<10:
+		return 11
+	case size < 1<<20:
+		return 6
+	default:
+		return 4
+	}
+}
+
+func compressLevel(data []byte, level int) ([]byte, error) {
+	dstBuf := bytes.NewBuffer(make([]byte, 0))
+	if err := CompressTo(dstBuf, data, level); err != nil {
 		return nil, err
 	}
 
 	return dstBuf.Bytes(), nil
 }
 
+// CompressTo compresses data with the given quality-level (0-11) and writes the complete brotli-stream to w,
+// e.g. to a http.ResponseWriter, without returning the compressed bytes. w is not closed.
+func CompressTo(w io.Writer, data []byte, level int) error {
+	writer := brotli.NewWriterLevel(w, level)
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
 func Decompress(data []byte) ([]byte, error) {
-	srcBuf := bytes.NewBuffer(data)
+	return DecompressFrom(bytes.NewReader(data))
+}
+
+// DecompressWithDict decompresses data which was compressed with CompressWithDict and the same dict.
+// An empty dict decompresses without a custom dictionary, any other dict fails with ErrDictionaryUnsupported.
+func DecompressWithDict(data, dict []byte) ([]byte, error) {
+	if len(dict) > 0 {
+		return nil, ErrDictionaryUnsupported
+	}
+
+	return Decompress(data)
+}
+
+// DecompressBestEffort decompresses data as brotli-stream and falls back to gzip if that fails,
+// e.g. while migrating systems which send either format. If both fail, the joined errors of both are returned.
+func DecompressBestEffort(data []byte) ([]byte, error) {
+	d, brotliErr := Decompress(data)
+	if brotliErr == nil {
+		return d, nil
+	}
+
+	reader, gzipErr := gzip.NewReader(bytes.NewReader(data))
+	if gzipErr == nil {
+		d, gzipErr = io.ReadAll(reader)
+		if gzipErr == nil {
+			return d, nil
+		}
+	}
+
+	return nil, fmt.Errorf("data is neither brotli nor gzip compressed: %w", errors.Join(brotliErr, gzipErr))
+}
+
+// NewDecompressReader returns a reader which decompresses the brotli-stream read from r.
+func NewDecompressReader(r io.Reader) io.Reader {
+	return brotli.NewReader(r)
+}
+
+// DecompressFrom decompresses the brotli-stream read from r until its end, e.g. from a http-response body,
+// without reading the compressed data into memory first.
+func DecompressFrom(r io.Reader) ([]byte, error) {
 	dstBuf := bytes.NewBuffer(make([]byte, 0))
-	reader := brotli.NewReader(srcBuf)
-	_, err := dstBuf.ReadFrom(reader)
+	_, err := dstBuf.ReadFrom(NewDecompressReader(r))
 	return dstBuf.Bytes(), err
 }
+
+// DecompressTo decompresses data into dst, overwriting its content and growing it only if its capacity
+// is not sufficient. The returned slice must be used instead of dst, as it may have been reallocated.
+// This allows to reuse buffers (e.g. from a sync.Pool) when decompressing many messages.
+func DecompressTo(dst []byte, data []byte) ([]byte, error) {
+	reader := brotli.NewReader(bytes.NewReader(data))
+	dst = dst[:0]
+
+	for {
+		if len(dst) == cap(dst) {
+			dst = append(dst, 0)[:len(dst)]
+		}
+
+		n, err := reader.Read(dst[len(dst):cap(dst)])
+		dst = dst[:len(dst)+n]
+		if err == io.EOF {
+			return dst, nil
+		}
+
+		if err != nil {
+			return dst, err
+		}
+	}
+}
+
+// selfTestPayload is compressed and decompressed by SelfTest
+var selfTestPayload = []byte(`{"name":"libstandard","kind":"self-test","values":[1,2,3,1,2,3,1,2,3]}`)
+
+// SelfTest compresses and decompresses a known payload and verifies that the result is equal,
+// e.g. as startup health-check that the codec works in the deployed environment. The payload is compressed
+// with the fastest quality-level, which allocates only a fraction of the memory of the higher levels.
+func SelfTest() error {
+	compressed, err := compressLevel(selfTestPayload, 1)
+	if err != nil {
+		return fmt.Errorf("compression self-test failed to compress: %w", err)
+	}
+
+	decompressed, err := DecompressTo(make([]byte, 0, len(selfTestPayload)), compressed)
+	if err != nil {
+		return fmt.Errorf("compression self-test failed to decompress: %w", err)
+	}
+
+	if !bytes.Equal(decompressed, selfTestPayload) {
+		return fmt.Errorf("compression self-test failed: got %d bytes which differ from the %d bytes of the payload", len(decompressed), len(selfTestPayload))
+	}
+
+	return nil
+}
@@ -1,13 +1,19 @@
 package libstandard
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+const compressionTestString = "This is a test-string. Lorem ipsum dolor sit amet, consetetur sadipscing elitr, sed diam nonumy eirmod tempor invidunt ut labore et dolore magna aliquyam erat, sed diam voluptua. At vero eos et accusam et justo duo dolores et ea rebum. Stet clita kasd gubergren, no sea takimata sanctus est Lorem ipsum dolor sit amet. Lorem ipsum dolor sit amet, consetetur sadipscing elitr, sed diam nonumy eirmod tempor invidunt ut labore et dolore magna aliquyam erat, sed diam voluptua. At vero eos et accusam et justo duo dolores et ea rebum. Stet clita kasd gubergren, no sea takimata sanctus est Lorem ipsum dolor sit amet."
+
 func TestCompression(t *testing.T) {
-	str := "This is a test-string. Lorem ipsum dolor sit amet, consetetur sadipscing elitr, sed diam nonumy eirmod tempor invidunt ut labore et dolore magna aliquyam erat, sed diam voluptua. At vero eos et accusam et justo duo dolores et ea rebum. Stet clita kasd gubergren, no sea takimata sanctus est Lorem ipsum dolor sit amet. Lorem ipsum dolor sit amet, consetetur sadipscing elitr, sed diam nonumy eirmod tempor invidunt ut labore et dolore magna aliquyam erat, sed diam voluptua. At vero eos et accusam et justo duo dolores et ea rebum. Stet clita kasd gubergren, no sea takimata sanctus est Lorem ipsum dolor sit amet."
+	str := compressionTestString
 	data := []byte(str)
 	b, err := Compress(data)
 	assert.NoError(t, err)
@@ -18,3 +24,197 @@ func TestCompression(t *testing.T) {
 	assert.Equal(t, len(d), len(data))
 	assert.Equal(t, string(d), str)
 }
+
+func TestDecompressTo(t *testing.T) {
+	data := []byte(compressionTestString)
+	b, err := Compress(data)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		dst  []byte
+	}{
+		{name: "nil", dst: nil},
+		{name: "too small", dst: make([]byte, 10)},
+		{name: "exact", dst: make([]byte, 0, len(data))},
+		{name: "oversized", dst: make([]byte, 42, len(data)*2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := DecompressTo(tt.dst, b)
+			assert.NoError(t, err)
+			assert.Equal(t, compressionTestString, string(d))
+
+			if cap(tt.dst) >= len(data) {
+				assert.Equal(t, &tt.dst[:1][0], &d[0])
+			}
+		})
+	}
+
+	_, err = DecompressTo(nil, []byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestDecompressFrom(t *testing.T) {
+	b, err := Compress([]byte(compressionTestString))
+	assert.NoError(t, err)
+
+	d, err := DecompressFrom(bytes.NewReader(b))
+	assert.NoError(t, err)
+	assert.Equal(t, compressionTestString, string(d))
+
+	pr, pw := io.Pipe()
+	go func() {
+		for i := 0; i < len(b); i += 16 {
+			end := i + 16
+			if end > len(b) {
+				end = len(b)
+			}
+			_, _ = pw.Write(b[i:end])
+		}
+		_ = pw.Close()
+	}()
+
+	d, err = DecompressFrom(pr)
+	assert.NoError(t, err)
+	assert.Equal(t, compressionTestString, string(d))
+
+	pr, pw = io.Pipe()
+	go func() {
+		_, _ = pw.Write(b[:len(b)/2])
+		_ = pw.CloseWithError(io.ErrClosedPipe)
+	}()
+
+	_, err = DecompressFrom(pr)
+	assert.Error(t, err)
+}
+
+func TestCompressTo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := CompressTo(buf, []byte(compressionTestString), 11)
+	assert.NoError(t, err)
+	assert.Less(t, buf.Len(), len(compressionTestString))
+
+	d, err := Decompress(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, compressionTestString, string(d))
+
+	b, err := Compress([]byte(compressionTestString))
+	assert.NoError(t, err)
+	assert.Equal(t, b, buf.Bytes())
+
+	pr, pw := io.Pipe()
+	_ = pr.Close()
+	assert.Error(t, CompressTo(pw, []byte(compressionTestString), 11))
+}
+
+func TestDecompressBestEffort(t *testing.T) {
+	b, err := Compress([]byte(compressionTestString))
+	assert.NoError(t, err)
+
+	d, err := DecompressBestEffort(b)
+	assert.NoError(t, err)
+	assert.Equal(t, compressionTestString, string(d))
+
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	_, err = writer.Write([]byte(compressionTestString))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	d, err = DecompressBestEffort(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, compressionTestString, string(d))
+
+	_, err = DecompressBestEffort([]byte("garbage"))
+	assert.ErrorContains(t, err, "neither brotli")
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestCompressWithDict(t *testing.T) {
+	data := []byte(compressionTestString)
+
+	b, err := CompressWithDict(data, nil, 5)
+	assert.NoError(t, err)
+	assert.Less(t, len(b), len(data))
+
+	d, err := DecompressWithDict(b, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, compressionTestString, string(d))
+
+	dict := []byte(`{"id":,"name":"","tags":[]}`)
+	_, err = CompressWithDict(data, dict, 5)
+	assert.ErrorIs(t, err, ErrDictionaryUnsupported)
+
+	_, err = DecompressWithDict(b, dict)
+	assert.ErrorIs(t, err, ErrDictionaryUnsupported)
+}
+
+func TestCompressAdaptive(t *testing.T) {
+	tests := []struct {
+		size  int
+		level int
+	}{
+		{size: 0, level: 11},
+		{size: 4<<10 - 1, level: 11},
+		{size: 4 << 10, level: 6},
+		{size: 1<<20 - 1, level: 6},
+		{size: 1 << 20, level: 4},
+		{size: 3 << 20, level: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.size), func(t *testing.T) {
+			assert.Equal(t, tt.level, adaptiveLevel(tt.size))
+
+			data := bytes.Repeat([]byte(compressionTestString), tt.size/len(compressionTestString)+1)[:tt.size]
+			b, err := CompressAdaptive(data)
+			assert.NoError(t, err)
+
+			expected, err := CompressWithDict(data, nil, tt.level)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, b)
+
+			d, err := Decompress(b)
+			assert.NoError(t, err)
+			assert.Equal(t, data, d)
+		})
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	assert.NoError(t, SelfTest())
+
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = SelfTest()
+	})
+	assert.LessOrEqual(t, allocs, float64(20))
+}
+
+func BenchmarkSelfTest(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = SelfTest()
+	}
+}
+
+func BenchmarkDecompress(b *testing.B) {
+	data, _ := Compress([]byte(compressionTestString))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = Decompress(data)
+	}
+}
+
+func BenchmarkDecompressTo(b *testing.B) {
+	data, _ := Compress([]byte(compressionTestString))
+	dst := make([]byte, 0, len(compressionTestString))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		dst, _ = DecompressTo(dst, data)
+	}
+}
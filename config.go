@@ -4,19 +4,37 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"bufio"
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
-
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/iancoleman/strcase"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"gopkg.in/yaml.v3"
 )
 
+// ConfigFileEnv is the conventional environment variable for the path of the config-file,
+// it is consulted if it is set as ReadOptions.ConfigFileEnv
+const ConfigFileEnv = "CONFIG_FILE"
+
 type DefaultFileConfig struct {
 	Name       string
 	Extensions []string
@@ -50,6 +68,19 @@ func ReadFromEnv(cfg interface{}) error {
 	return Read(cfg, nil, "", DefaultFileConfig{})
 }
 
+// ReadFromEnvWithPrefix works like ReadFromEnv, but prepends prefix to the names of all environment variables,
+// including those of nested structures with an env-prefix, e.g. "MYAPP_" and `env:"PORT"` look up MYAPP_PORT.
+//
+// Example:
+//
+//	 err := config.ReadFromEnvWithPrefix(&cfg, "MYAPP_")
+//	 if err != nil {
+//	     ...
+//	 }
+func ReadFromEnvWithPrefix(cfg interface{}, prefix string) error {
+	return read(cfg, nil, "", DefaultFileConfig{}, ReadOptions{EnvPrefix: prefix}, nil)
+}
+
 // ReadFromFile reads configuration from a file and environment variables, parses them depending on tags in structure provided.
 // Then it reads and parses
 //
@@ -99,6 +130,65 @@ func ReadFromFlags(cfg interface{}, flags *pflag.FlagSet) error {
 	return Read(cfg, flags, "", DefaultFileConfig{})
 }
 
+// ReadFromFlagSets works like ReadFromFlags, but reads from several flag-sets, e.g. composed from subcommands.
+// The sets are applied in the given order: if a flag exists in more than one set, the one of the last set wins,
+// unless it was only set there by its default while it was changed in an earlier set.
+//
+// Example:
+//
+//	 err := config.ReadFromFlagSets(&cfg, rootCmd.PersistentFlags(), cmd.Flags())
+//	 if err != nil {
+//	     ...
+//	 }
+func ReadFromFlagSets(cfg interface{}, flagSets ...*pflag.FlagSet) error {
+	return ReadFromFlags(cfg, mergeFlagSets(flagSets...))
+}
+
+// mergeFlagSets combines the flags of all sets into a new set, shorthands are dropped as they may collide
+func mergeFlagSets(flagSets ...*pflag.FlagSet) *pflag.FlagSet {
+	flags := make(map[string]*pflag.Flag)
+	names := make([]string, 0)
+
+	for _, flagSet := range flagSets {
+		if flagSet == nil {
+			continue
+		}
+
+		flagSet.VisitAll(func(flag *pflag.Flag) {
+			existing, ok := flags[flag.Name]
+			if !ok {
+				names = append(names, flag.Name)
+			} else if existing.Changed && !flag.Changed {
+				return
+			}
+
+			merged := *flag
+			merged.Shorthand = ""
+			flags[flag.Name] = &merged
+		})
+	}
+
+	merged := pflag.NewFlagSet("merged", pflag.ContinueOnError)
+	for _, name := range names {
+		merged.AddFlag(flags[name])
+	}
+
+	return merged
+}
+
+// ReadFromFlagsAndFile works like Read, but ignores environment variables, e.g. for reproducible runs of a tool.
+// The env-default values still apply.
+//
+// Example:
+//
+//	 err := config.ReadFromFlagsAndFile(&cfg, cmd.Flags(), "config.yml", DefaultFileConfig{})
+//	 if err != nil {
+//	     ...
+//	 }
+func ReadFromFlagsAndFile(cfg interface{}, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig) error {
+	return read(cfg, flags, file, defaultCfg, ReadOptions{SkipEnv: true}, nil)
+}
+
 // Read reads configuration from a file, environment variables and cmd-flags, parses them depending on tags in structure provided.
 // Then it reads and parses
 //
@@ -122,35 +212,232 @@ func ReadFromFlags(cfg interface{}, flags *pflag.FlagSet) error {
 //	     ...
 //	 }
 func Read(cfg interface{}, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig) error {
-	metaInfo, err := readStructMetadata(cfg)
+	return read(cfg, flags, file, defaultCfg, ReadOptions{}, nil)
+}
+
+// MustRead works like Read, but panics if the configuration can't be read. It is meant for main-functions
+// which can't continue without a valid configuration anyway.
+//
+// Example:
+//
+//	 var cfg ConfigDatabase
+//	 config.MustRead(&cfg, cmd.Flags(), "config.yml", DefaultFileConfig{})
+func MustRead(cfg interface{}, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig) {
+	if err := Read(cfg, flags, file, defaultCfg); err != nil {
+		panic(fmt.Errorf("An error occurred while reading the config into %T! %w", cfg, err))
+	}
+}
+
+// ReadOptions holds optional settings for ReadWithOptions
+type ReadOptions struct {
+	// SkipEmptyEnv treats environment variables with an empty value as unset, so the defaults apply
+	SkipEmptyEnv bool
+	// EnvPrefix is prepended to the names of all environment variables, see ReadFromEnvWithPrefix
+	EnvPrefix string
+	// EnvPrefixSeparator joins the env-prefixes of nested structures and the env-names like an
+	// env-prefix-separator tag on every structure without an own one, e.g. "." matches DB.DEBUG.
+	// Typical values are "_" and ".", empty concatenates the prefixes as they are
+	EnvPrefixSeparator string
+	// SkipEnv ignores all environment variables, only the env-default values apply. See ReadFromFlagsAndFile
+	SkipEnv bool
+	// ConfigFileEnv is the environment variable holding the path of the config-file, e.g. ConfigFileEnv, which is
+	// used if no file is given explicitly, before searching the default file. No variable is consulted if it is empty
+	ConfigFileEnv string
+	// ReadEnvFiles reads the value of an unset environment variable X from the file named by X_FILE,
+	// e.g. DB_PASSWORD_FILE=/run/secrets/db for Docker secrets. Surrounding whitespace is trimmed
+	ReadEnvFiles bool
+	// MergeYAMLDocuments decodes all "---"-separated documents of a YAML-file into the structure,
+	// so later documents override the values of earlier ones. By default only the first document is read.
+	// A parser registered for yaml with RegisterParser takes precedence and is used unchanged
+	MergeYAMLDocuments bool
+	// DeriveFlagNames matches fields without a flag tag to the flag named like their yaml- or json-tag,
+	// or like the kebab-cased field name if they have neither, e.g. "ListenPort" to "listen-port".
+	// Fields of nested structures are prefixed with the names of their parents, e.g. "db-host" for DB.Host,
+	// the fields of embedded structures are promoted. Nested structures themselves don't get a flag
+	DeriveFlagNames bool
+	// Defaulters compute raw default values for fields which are still zero after reading all sources.
+	// The keys are the field names, fields of nested structures are prefixed with their parents, e.g. "Database.Host"
+	Defaulters map[string]func() string
+	// Section is the dotted path of the value in the config-file which is decoded into the structure,
+	// e.g. "services.api". The whole file is decoded if it is empty. See ReadSection
+	Section string
+	// SecretResolver resolves the values of environment variables of fields tagged with env-secret-ref
+	SecretResolver SecretResolver
+}
+
+// ReadWithOptions works like Read and applies the given options.
+//
+// Example:
+//
+//	 err := config.ReadWithOptions(&cfg, cmd.Flags(), "config.yml", DefaultFileConfig{}, ReadOptions{SkipEmptyEnv: true})
+//	 if err != nil {
+//	     ...
+//	 }
+func ReadWithOptions(cfg interface{}, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig, opts ReadOptions) error {
+	return read(cfg, flags, file, defaultCfg, opts, nil)
+}
+
+// ReadWithDefaulters works like Read, but computes the defaults of fields which are still zero after
+// reading all sources with the given functions, e.g. to default to the number of CPUs or the hostname.
+//
+// Example:
+//
+//	 err := config.ReadWithDefaulters(&cfg, map[string]func() string{
+//	     "Workers": func() string { return strconv.Itoa(runtime.NumCPU()) },
+//	 }, cmd.Flags(), "config.yml", DefaultFileConfig{})
+//	 if err != nil {
+//	     ...
+//	 }
+func ReadWithDefaulters(cfg interface{}, defaulters map[string]func() string, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig) error {
+	return read(cfg, flags, file, defaultCfg, ReadOptions{Defaulters: defaulters}, nil)
+}
+
+// read implements Read and records the source of each field in sources, if it is not nil
+func read(cfg interface{}, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig, opts ReadOptions, sources map[string]Source) error {
+	metaInfo, err := readStructMetadataWithOptions(cfg, opts)
 	if err != nil {
 		return err
 	}
 
+	for _, meta := range metaInfo {
+		recordSource(sources, meta, SourceNone)
+		for j := range meta.envList {
+			meta.envList[j] = opts.EnvPrefix + meta.envList[j]
+		}
+		for j := range meta.indexedEnvs {
+			meta.indexedEnvs[j] = opts.EnvPrefix + meta.indexedEnvs[j]
+		}
+	}
+
+	if file == "" && opts.ConfigFileEnv != "" && !opts.SkipEnv {
+		file = os.Getenv(opts.ConfigFileEnv)
+	}
+
 	if file == "" {
-		file = findDefaultFile(defaultCfg)
+		file = FindDefaultFile(defaultCfg)
 	}
 
 	if file != "" {
-		err = parseFile(file, cfg)
+		snapshot := snapshotFields(metaInfo)
+		err = parseFile(file, cfg, opts)
 		if err != nil {
 			return err
 		}
+		recordChangedFields(sources, metaInfo, snapshot, SourceFile)
 	}
 
-	err = readEnvVars(cfg, metaInfo)
+	err = readEnvVars(cfg, metaInfo, opts, sources)
 	if err != nil {
 		return err
 	}
 
 	if flags != nil {
-		err = parseFlags(flags, cfg, metaInfo)
+		err = parseFlags(flags, cfg, metaInfo, opts, sources)
 		if err != nil {
 			return err
 		}
 	}
 
-	return checkRequired(metaInfo)
+	err = applyDefaulters(metaInfo, opts.Defaulters, sources)
+	if err != nil {
+		return err
+	}
+
+	err = checkRequired(metaInfo)
+	if err != nil {
+		return err
+	}
+
+	return checkValidation(metaInfo)
+}
+
+// Reload re-reads the configuration into an already populated structure, e.g. on SIGHUP.
+// Unlike Read, it does not merge on top of the existing values: the configuration is read
+// into a zero value of the same type first, so keys removed from the file since the last
+// load fall back to their defaults instead of keeping stale values.
+// cfg is only overwritten if the read succeeds, otherwise it is left untouched.
+//
+// Example:
+//
+//	 err := config.Reload(&cfg, cmd.Flags(), "config.yml", DefaultFileConfig{})
+//	 if err != nil {
+//	     ...
+//	 }
+func Reload(cfg interface{}, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig) error {
+	target := reflect.ValueOf(cfg)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("wrong type %v", target.Kind())
+	}
+
+	fresh := reflect.New(target.Elem().Type())
+	if err := Read(fresh.Interface(), flags, file, defaultCfg); err != nil {
+		return err
+	}
+
+	target.Elem().Set(fresh.Elem())
+	return nil
+}
+
+// CheckConfig reads and validates the configuration like Read, e.g. for a "--check" flag of a CLI which exits
+// non-zero on failure. It has no side effects: the configuration is read into a deep copy of cfg, so values set
+// in code before are taken into account like by Read, while cfg itself is left untouched. The logging is not set up.
+//
+// Example:
+//
+//	 if err := config.CheckConfig(&cfg, cmd.Flags(), "config.yml", DefaultFileConfig{}); err != nil {
+//	     fmt.Fprintln(os.Stderr, err)
+//	     os.Exit(1)
+//	 }
+func CheckConfig(cfg interface{}, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig) error {
+	target := reflect.ValueOf(cfg)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("wrong type %v", target.Kind())
+	}
+
+	check := reflect.New(target.Elem().Type())
+	check.Elem().Set(deepCopy(target.Elem()))
+	return Read(check.Interface(), flags, file, defaultCfg)
+}
+
+// ApplyOverrides applies "key=value" overrides to the already read configuration, e.g. from repeated
+// "--set" flags. The key is either one of the environment variable names of a field or its dotted
+// field path like "Database.Port". Overrides are applied in the given order, so later ones win.
+//
+// Example:
+//
+//	 overrides, _ := cmd.Flags().GetStringArray("set")
+//	 err := config.ApplyOverrides(&cfg, overrides)
+func ApplyOverrides(cfg interface{}, overrides []string) error {
+	metaInfo, err := readStructMetadata(cfg)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]structMeta)
+	for _, meta := range metaInfo {
+		fields[meta.fieldPath] = meta
+		for _, env := range meta.envList {
+			fields[env] = meta
+		}
+	}
+
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok {
+			return fmt.Errorf("invalid override %q, expected key=value", override)
+		}
+
+		meta, ok := fields[strings.TrimSpace(key)]
+		if !ok {
+			return fmt.Errorf("override for unknown field %q", key)
+		}
+
+		if err := parseValue(meta.fieldValue, value, meta.parseOptions); err != nil {
+			return fmt.Errorf("invalid override of field %q: %w", meta.fieldPath, err)
+		}
+	}
+
+	return nil
 }
 
 const (
@@ -160,18 +447,59 @@ const (
 
 // Supported tags
 const (
-	// Name of the environment variable or a list of names
+	// Name of the environment variable or a comma-separated list of names.
+	// The names are looked up in the given order and the first variable which is set wins,
+	// so `env:"NEW_NAME,OLD_NAME"` keeps OLD_NAME working as fallback after a rename.
+	// An env-prefix of the enclosing structure is applied to every name of the list.
+	// A default value can be given inline after a colon, e.g. `env:"PORT,DB_PORT:5432"`,
+	// everything after the first colon is the default and env-default takes precedence.
 	TagEnv = "env"
 	// Default value
 	TagEnvDefault = "env-default"
-	// Flag name
+	// Flag name, optionally followed by a single-character shorthand, e.g. "port,p"
 	TagFlagName = "flag"
-	// Custom list and map separator
+	// Custom list and map separator, on a structure it is inherited by all nested fields without own separator.
+	// In slices a separator preceded by a backslash is part of the item, e.g. `a,b\,c`
 	TagEnvSeparator = "env-separator"
 	// Flag to mark a field as required
 	TagEnvRequired = "env-required"
-	// Flag to specify prefix for structure fields
+	// Name of a group of fields of which at least one is required
+	TagEnvRequiredGroup = "env-required-group"
+	// Flag to set a bool field to true if the environment variable is present, regardless of its value
+	TagEnvPresentTrue = "env-present-true"
+	// Flag to treat the value of the environment variable as reference, which is resolved with the
+	// SecretResolver of the ReadOptions, e.g. "secret/data/db#password" for Vault
+	TagEnvSecretRef = "env-secret-ref"
+	// Flag to specify prefix for structure fields. A comma-separated list of prefixes is tried in the given
+	// order, so `env-prefix:"NEW_,OLD_"` keeps the OLD_ variables working as fallback after a rename
 	TagEnvPrefix = "env-prefix"
+	// Separator appended to the env-prefix if it doesn't end with it already, e.g. "_" joins
+	// the nested prefixes "DB" and "POOL" to "DB_POOL_". It is inherited by nested structures,
+	// without it the prefixes are concatenated as they are
+	TagEnvPrefixSeparator = "env-prefix-separator"
+	// Flag to split slices like a CSV-record, so items can be quoted to contain the separator, e.g. `a,"b,c",d`
+	TagEnvCSV = "env-csv"
+	// Base for parsing integer values, e.g. "10" to parse "0755" as decimal instead of octal
+	TagEnvBase = "env-base"
+	// Flag to parse integer values with a unit suffix into a number of bytes, e.g. "10MB" or "512KiB".
+	// Supported units are KB, MB, GB (powers of 1000) and KiB, MiB, GiB (powers of 1024), plain integers are bytes
+	TagEnvSize = "env-size"
+	// Flag to parse float values with a trailing "%" as percentage, e.g. "75%" to 0.75. Values without "%" are parsed as they are
+	TagEnvPercent = "env-percent"
+	// Flag to parse a single character into a rune (int32) or byte (uint8) field, e.g. "," to 44.
+	// Without the flag the value is parsed as number
+	TagEnvChar = "env-char"
+	// Name of indexed environment variables collected into a slice, e.g. "ITEM" reads ITEM_0, ITEM_1, ...
+	// until the first missing index. It is used if none of the env-names is set
+	TagEnvIndexed = "env-indexed"
+	// How the value of an environment variable is merged into a slice which was populated before, e.g. from the
+	// config-file: "replace" (default) overwrites the slice, "append" appends the parsed items to it
+	TagEnvMerge = "env-merge"
+	// Concrete type to parse the value of an interface{} field into: int, float, bool, string or duration
+	TagEnvType = "env-type"
+	// Comma-separated validation rules, e.g. "min=1,max=65535". "dive" applies the following rules
+	// to each element of a slice instead of the slice itself
+	TagValidate = "validate"
 )
 
 // Setter is an interface for a custom value setter.
@@ -191,7 +519,30 @@ type Setter interface {
 	SetValue(string) error
 }
 
-func findDefaultFile(defaultCfg DefaultFileConfig) string {
+// SecretResolver resolves references to secrets in an external secret manager, e.g. Vault or AWS Secrets Manager.
+// It is used for fields tagged with `env-secret-ref:"true"`, whose environment variable holds the reference.
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// resolveSecretRef resolves the reference read from the environment variable with the SecretResolver of opts
+func resolveSecretRef(env, ref string, opts ReadOptions) (string, error) {
+	if opts.SecretResolver == nil {
+		return "", fmt.Errorf("%s holds a secret reference, but no SecretResolver is configured", env)
+	}
+
+	value, err := opts.SecretResolver.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("resolving secret reference of %s: %w", env, err)
+	}
+
+	return value, nil
+}
+
+// FindDefaultFile returns the path of the config-file Read would use if no file is given explicitly,
+// or an empty string if none of the paths contains a file with the name and one of the extensions.
+// The paths are searched in the given order, the extensions in their order per path.
+func FindDefaultFile(defaultCfg DefaultFileConfig) string {
 	for _, p := range defaultCfg.Paths {
 		for _, ext := range defaultCfg.Extensions {
 			fullPath := filepath.Join(p, defaultCfg.Name+"."+ext)
@@ -208,33 +559,97 @@ func findDefaultFile(defaultCfg DefaultFileConfig) string {
 	return ""
 }
 
+func applyDefaulters(metaInfo []structMeta, defaulters map[string]func() string, sources map[string]Source) error {
+	if len(defaulters) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool, len(metaInfo))
+	for _, meta := range metaInfo {
+		known[meta.fieldPath] = true
+		defaulter, ok := defaulters[meta.fieldPath]
+		if !ok || !meta.isFieldValueZero() {
+			continue
+		}
+
+		if err := parseValue(meta.fieldValue, defaulter(), meta.parseOptions); err != nil {
+			return fmt.Errorf("invalid computed default of field %q: %w", meta.fieldPath, err)
+		}
+		recordSource(sources, meta, SourceDefault)
+	}
+
+	for name := range defaulters {
+		if !known[name] {
+			return fmt.Errorf("defaulter for unknown field %q", name)
+		}
+	}
+
+	return nil
+}
+
 func checkRequired(metaInfo []structMeta) error {
+	groups := make([]string, 0)
+	groupFields := make(map[string][]string)
+	groupSet := make(map[string]bool)
+
 	for _, meta := range metaInfo {
 		if meta.required && meta.isFieldValueZero() {
 			err := fmt.Errorf("field %q is required but the value is not provided",
 				meta.fieldName)
 			return err
 		}
+
+		if meta.requiredGroup != "" {
+			if _, ok := groupFields[meta.requiredGroup]; !ok {
+				groups = append(groups, meta.requiredGroup)
+			}
+			groupFields[meta.requiredGroup] = append(groupFields[meta.requiredGroup], meta.fieldName)
+			groupSet[meta.requiredGroup] = groupSet[meta.requiredGroup] || !meta.isFieldValueZero()
+		}
+	}
+
+	for _, group := range groups {
+		if !groupSet[group] {
+			return fmt.Errorf("at least one field of group %q (%s) is required but no value is provided",
+				group, strings.Join(groupFields[group], ", "))
+		}
 	}
 
 	return nil
 }
 
-func parseFlags(flags *pflag.FlagSet, cfg interface{}, metaInfo []structMeta) error {
+func parseFlags(flags *pflag.FlagSet, cfg interface{}, metaInfo []structMeta, opts ReadOptions, sources map[string]Source) error {
 	for _, meta := range metaInfo {
 		var rawValue *string
+		var items []string
+		source := SourceDefault
+		parseOpts := meta.parseOptions
+
+		flagName := meta.flagName
+		if flagName == "" && opts.DeriveFlagNames {
+			flagName = meta.derivedFlagName
+		}
 
-		if meta.flagName != "" {
-			flag := flags.Lookup(meta.flagName)
+		if flagName != "" {
+			flag := flags.Lookup(flagName)
 			if flag != nil && flag.Changed {
 				s := flag.Value.String()
 				rawValue = &s
+				items = flagSliceItems(flag, meta.fieldValue)
+				parseOpts = flagParseOptions(flag, parseOpts)
+				source = SourceFlag
 			} else if flag != nil && flag.DefValue != "" && (meta.isFieldValueZero() || (meta.defValue != nil && meta.fieldValue.String() == *meta.defValue)) {
-				rawValue = &flag.DefValue
+				// an unset slice-flag with an empty default doesn't provide a value, e.g. for env-required
+				if items = flagSliceItems(flag, meta.fieldValue); items == nil || len(items) > 0 {
+					rawValue = &flag.DefValue
+					parseOpts = flagParseOptions(flag, parseOpts)
+				} else {
+					items = nil
+				}
 			}
 		}
 
-		if rawValue == nil && meta.isFieldValueZero() {
+		if rawValue == nil && meta.isFieldValueEmpty() {
 			rawValue = meta.defValue
 		}
 
@@ -242,22 +657,79 @@ func parseFlags(flags *pflag.FlagSet, cfg interface{}, metaInfo []structMeta) er
 			continue
 		}
 
-		if err := parseValue(meta.fieldValue, *rawValue, meta.separator); err != nil {
+		var err error
+		if items != nil {
+			err = parseSliceItems(meta.fieldValue, items, parseOpts)
+		} else {
+			err = parseValue(meta.fieldValue, *rawValue, parseOpts)
+		}
+
+		if err != nil {
+			return err
+		}
+		recordSource(sources, meta, source)
+	}
+
+	return nil
+}
+
+// flagParseOptions returns the options to parse the value of the flag with. Typed flags like Int or IntSlice
+// render their values in decimal, so the env-base only applies to string flags
+func flagParseOptions(flag *pflag.Flag, opts parseOptions) parseOptions {
+	switch flag.Value.Type() {
+	case "string", "stringSlice", "stringArray":
+	default:
+		opts.base = 10
+	}
+
+	return opts
+}
+
+// flagSliceItems returns the items of a slice-valued flag like StringSlice or IntSlice, if it is bound to a slice field.
+// Parsing the items directly avoids splitting the bracketed and possibly quoted string-representation of the flag.
+func flagSliceItems(flag *pflag.Flag, field reflect.Value) []string {
+	sliceValue, ok := flag.Value.(pflag.SliceValue)
+	if !ok || field.Kind() != reflect.Slice || field.Type().Elem().Kind() == reflect.Uint8 {
+		return nil
+	}
+
+	items := sliceValue.GetSlice()
+	if items == nil {
+		items = []string{}
+	}
+
+	return items
+}
+
+// parseSliceItems parses each item into an element of the slice field
+func parseSliceItems(field reflect.Value, items []string, opts parseOptions) error {
+	sliceValue := reflect.MakeSlice(field.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := parseValue(sliceValue.Index(i), item, opts); err != nil {
 			return err
 		}
 	}
 
+	field.Set(sliceValue)
 	return nil
 }
 
 // parseFile parses configuration file according to it's extension
 //
-// Currently following file extensions are supported:
+// The following file extensions are supported out of the box, others can be added with RegisterParser:
 //
 // - yaml
 //
 // - json
-func parseFile(path string, cfg interface{}) error {
+//
+// - ini
+//
+// - hcl
+//
+// - xml
+//
+// - properties
+func parseFile(path string, cfg interface{}, opts ReadOptions) error {
 	// open the configuration file
 	/* #nosec */
 	f, err := os.OpenFile(path, os.O_RDONLY|os.O_SYNC, 0)
@@ -269,86 +741,642 @@ func parseFile(path string, cfg interface{}) error {
 	defer f.Close()
 
 	// parse the file depending on the file type
-	switch ext := strings.ToLower(filepath.Ext(path)); ext {
-	case ".yaml", ".yml":
-		err = parseYAML(f, cfg)
-	case ".json":
-		err = parseJSON(f, cfg)
-	default:
+	ext := strings.ToLower(filepath.Ext(path))
+	parser := lookupParser(ext)
+	if opts.MergeYAMLDocuments && isParser(parser, parseYAML) {
+		parser = parseYAMLDocuments
+	}
+
+	if opts.Section != "" {
+		parser, err = sectionParser(ext, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if parser == nil {
 		return fmt.Errorf("file format '%s' doesn't supported by the parser", ext)
 	}
+
+	if usesAliases(cfg) {
+		// the content is decoded twice, into the structure and into a map to look up the alias keys
+		var content []byte
+		if content, err = io.ReadAll(f); err == nil {
+			if err = parser(bytes.NewReader(content), cfg); err == nil {
+				err = applyAliases(cfg, parser, content, ext)
+			}
+		}
+	} else {
+		err = parser(f, cfg)
+	}
+
 	if err != nil {
 		return fmt.Errorf("config file parsing error: %s", err.Error())
 	}
 	return nil
 }
 
+// ParserFunc decodes the content of a config-file from the reader into the given data structure
+type ParserFunc func(r io.Reader, cfg interface{}) error
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]ParserFunc{
+		".yaml":       parseYAML,
+		".yml":        parseYAML,
+		".json":       parseJSON,
+		".ini":        parseINI,
+		".hcl":        parseHCL,
+		".xml":        parseXML,
+		".properties": parseProperties,
+	}
+)
+
+// RegisterParser registers a parser for config-files with the given extension (e.g. "xyz" or ".xyz").
+// Extensions are matched case-insensitive. Registering an already known extension overrides
+// the existing parser, including the built-in yaml and json parsers.
+func RegisterParser(ext string, fn func(io.Reader, interface{}) error) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[normalizeExt(ext)] = fn
+}
+
+// lookupParser returns the parser registered for the extension or nil
+func lookupParser(ext string) ParserFunc {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	return parsers[normalizeExt(ext)]
+}
+
+// isParser reports whether parser is the given built-in parser, so a registered override can be told apart
+func isParser(parser ParserFunc, builtin ParserFunc) bool {
+	return parser != nil && reflect.ValueOf(parser).Pointer() == reflect.ValueOf(builtin).Pointer()
+}
+
+// normalizeExt lower-cases the extension and ensures the leading dot
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
 // parseYAML parses YAML from reader to data structure
 func parseYAML(r io.Reader, str interface{}) error {
 	return yaml.NewDecoder(r).Decode(str)
 }
 
+// parseYAMLDocuments parses all documents of the YAML-stream from reader into the same data structure
+func parseYAMLDocuments(r io.Reader, str interface{}) error {
+	decoder := yaml.NewDecoder(r)
+	for {
+		err := decodeYAMLMerged(str, decoder.Decode)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// decodeYAMLMerged decodes the next document into str. Maps are merged deeply like structures,
+// instead of replacing the nested maps of the previous documents
+func decodeYAMLMerged(str interface{}, decode func(interface{}) error) error {
+	m, ok := str.(*map[string]interface{})
+	if !ok || *m == nil {
+		return decode(str)
+	}
+
+	var next map[string]interface{}
+	if err := decode(&next); err != nil {
+		return err
+	}
+
+	*m = DeepMerge(*m, next)
+	return nil
+}
+
 // parseJSON parses JSON from reader to data structure
 func parseJSON(r io.Reader, str interface{}) error {
 	return json.NewDecoder(r).Decode(str)
 }
 
-// structMeta is a structure metadata entity
-type structMeta struct {
-	envList    []string
-	flagName   string
-	fieldName  string
-	fieldValue reflect.Value
-	defValue   *string
-	separator  string
-	required   bool
-}
-
-// isFieldValueZero determines if fieldValue empty or not
-func (sm *structMeta) isFieldValueZero() bool {
-	return isZero(sm.fieldValue)
+// parseXML parses XML from reader to data structure
+func parseXML(r io.Reader, str interface{}) error {
+	return xml.NewDecoder(r).Decode(str)
 }
 
-// readStructMetadata reads structure metadata (types, tags, etc.)
-func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
-	type cfgNode struct {
-		Val    interface{}
-		Prefix string
+// parseINI parses INI from reader to data structure.
+// Keys before the first section are assigned to the top-level fields, keys of a section
+// to the fields of the nested structure matching the section name.
+func parseINI(r io.Reader, str interface{}) error {
+	root := reflect.ValueOf(str)
+	if root.Kind() == reflect.Ptr {
+		root = root.Elem()
 	}
 
-	cfgStack := []cfgNode{{cfgRoot, ""}}
-	metas := make([]structMeta, 0)
+	if root.Kind() != reflect.Struct {
+		return fmt.Errorf("wrong type %v", root.Kind())
+	}
 
-	for i := 0; i < len(cfgStack); i++ {
+	section, sectionSeparator := root, DefaultSeparator
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "#") {
+			continue
+		}
 
-		s := reflect.ValueOf(cfgStack[i].Val)
-		sPrefix := cfgStack[i].Prefix
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return fmt.Errorf("line %d: invalid section %q", line, text)
+			}
 
-		// unwrap pointer
-		if s.Kind() == reflect.Ptr {
-			s = s.Elem()
+			section = reflect.Value{}
+			if field, fType, ok := lookupFileField(root, strings.TrimSpace(text[1:len(text)-1])); ok && field.Kind() == reflect.Struct {
+				section, sectionSeparator = field, fieldSeparator(fType, DefaultSeparator)
+			}
+			continue
 		}
 
-		// process only structures
-		if s.Kind() != reflect.Struct {
-			return nil, fmt.Errorf("wrong type %v", s.Kind())
+		kvPair := strings.SplitN(text, "=", 2)
+		if len(kvPair) != 2 {
+			return fmt.Errorf("line %d: invalid key-value pair %q", line, text)
 		}
-		typeInfo := s.Type()
 
-		// read tags
-		for idx := 0; idx < s.NumField(); idx++ {
-			fType := typeInfo.Field(idx)
+		if !section.IsValid() {
+			continue
+		}
+
+		// keys naming nested structures are skipped, their fields are set in their own section
+		field, fType, ok := lookupFileField(section, strings.TrimSpace(kvPair[0]))
+		if !ok || field.Kind() == reflect.Struct && !isSpecialType(field.Type()) {
+			continue
+		}
+
+		separator := fieldSeparator(fType, sectionSeparator)
+		value := strings.TrimSpace(kvPair[1])
+		if len(value) >= 2 && strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+			value = value[1 : len(value)-1]
+		}
+
+		if err := parseValue(field, value, parseOptions{separator: separator}); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// fieldSeparator returns the env-separator of the field or the one inherited from its parent structures
+func fieldSeparator(fType reflect.StructField, inherited string) string {
+	if sep, ok := fType.Tag.Lookup(TagEnvSeparator); ok {
+		return sep
+	}
+
+	return inherited
+}
+
+// parseProperties parses a Java-style properties-file from reader to data structure.
+// Dotted keys (e.g. "database.host") address the fields of nested structures, lines ending with
+// a backslash are continued on the next line and lines starting with "#" or "!" are comments.
+// Keys are separated from their values by "=", ":" or whitespace, escape sequences like "\\", "\=",
+// "\n" and "\uXXXX" are unescaped in keys and values.
+func parseProperties(r io.Reader, str interface{}) error {
+	root := reflect.ValueOf(str)
+	if root.Kind() == reflect.Ptr {
+		root = root.Elem()
+	}
+
+	if root.Kind() != reflect.Struct {
+		return fmt.Errorf("wrong type %v", root.Kind())
+	}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		start := line
+		text := strings.TrimLeft(scanner.Text(), " \t\f")
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "!") {
+			continue
+		}
+
+		for continuesLine(text) && scanner.Scan() {
+			line++
+			text = text[:len(text)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+		if continuesLine(text) {
+			text = text[:len(text)-1]
+		}
+
+		key, value, err := splitProperty(text)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", start, err)
+		}
+
+		field, ok, separator := root, true, DefaultSeparator
+		for _, key := range strings.Split(key, ".") {
+			if field.Kind() != reflect.Struct {
+				ok = false
+				break
+			}
+
+			var fType reflect.StructField
+			if field, fType, ok = lookupFileField(field, key); !ok {
+				break
+			}
+			separator = fieldSeparator(fType, separator)
+		}
+
+		if !ok || field.Kind() == reflect.Struct {
+			continue
+		}
+
+		if err := parseValue(field, value, parseOptions{separator: separator}); err != nil {
+			return fmt.Errorf("line %d: %w", start, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// continuesLine reports whether the line of a properties-file ends with an odd number of backslashes,
+// an even number are escaped backslashes
+func continuesLine(text string) bool {
+	n := 0
+	for n < len(text) && text[len(text)-1-n] == '\\' {
+		n++
+	}
+
+	return n%2 == 1
+}
+
+// splitProperty splits the line of a properties-file at the first unescaped "=", ":" or whitespace
+// into the unescaped key and value. A key without value has an empty value.
+func splitProperty(text string) (string, string, error) {
+	end := len(text)
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+		} else if strings.IndexByte("=: \t\f", text[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	// whitespace around the separator is ignored, whitespace alone separates as well
+	rest := strings.TrimLeft(text[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	key, err := unescapeProperty(text[:end])
+	if err != nil {
+		return "", "", err
+	}
+
+	value, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+
+	return key, value, nil
+}
+
+// unescapeProperty resolves the escape sequences of a properties-file and trims unescaped trailing whitespace
+func unescapeProperty(text string) (string, error) {
+	var b strings.Builder
+	keep := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			if c != ' ' && c != '\t' && c != '\f' {
+				keep = b.Len()
+			}
+			continue
+		}
+
+		if i++; i == len(text) {
+			break
+		}
+
+		switch c = text[i]; c {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(text) {
+				return "", fmt.Errorf("invalid unicode escape %q", text[i-1:])
+			}
+
+			r, err := strconv.ParseUint(text[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", text[i-1:i+5])
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(c)
+		}
+		keep = b.Len()
+	}
+
+	return b.String()[:keep], nil
+}
+
+// parseHCL parses HCL from reader to data structure.
+// Attributes are assigned to the fields, blocks to the nested structures matching the block type.
+// Labeled blocks of the same type are collected into a map keyed by their labels, duplicate blocks are an error.
+func parseHCL(r io.Reader, str interface{}) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	file, diags := hclsyntax.ParseConfig(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	values, err := hclBodyToMap(file.Body.(*hclsyntax.Body))
+	if err != nil {
+		return err
+	}
+
+	root := reflect.ValueOf(str)
+	if root.Kind() == reflect.Ptr {
+		root = root.Elem()
+	}
+
+	if root.Kind() != reflect.Struct {
+		return fmt.Errorf("wrong type %v", root.Kind())
+	}
+
+	return decodeFileMap(values, root)
+}
+
+// hclBodyToMap converts the attributes and blocks of the HCL body to a map
+func hclBodyToMap(body *hclsyntax.Body) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	for name, attr := range body.Attributes {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		raw, err := ctyjson.SimpleJSONValue{Value: value}.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		var decoded interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, err
+		}
+		values[name] = decoded
+	}
+
+	for _, block := range body.Blocks {
+		blockValues, err := hclBodyToMap(block.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		// blocks of the same type are merged by their labels, e.g. server "a" {} and server "b" {}
+		target, key := values, block.Type
+		for _, label := range block.Labels {
+			nested, ok := target[key].(map[string]interface{})
+			if !ok {
+				if _, exists := target[key]; exists {
+					break
+				}
+				nested = make(map[string]interface{})
+				target[key] = nested
+			}
+			target, key = nested, label
+		}
+
+		if _, exists := target[key]; exists {
+			name := strings.Join(append([]string{block.Type}, block.Labels...), " ")
+			return nil, fmt.Errorf("%s: duplicate block %q", block.DefRange(), name)
+		}
+		target[key] = blockValues
+	}
+
+	return values, nil
+}
+
+// decodeFileMap assigns the values of the map to the fields of the structure matching the keys
+func decodeFileMap(values map[string]interface{}, s reflect.Value) error {
+	for key, value := range values {
+		field, _, ok := lookupFileField(s, key)
+		if !ok {
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && field.Kind() == reflect.Struct {
+			if err := decodeFileMap(nested, field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		if err := yaml.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("field %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// lookupFileField finds the settable field of the structure whose yaml-, json- or hcl-name or
+// field-name matches the key case-insensitively
+func lookupFileField(s reflect.Value, key string) (reflect.Value, reflect.StructField, bool) {
+	typeInfo := s.Type()
+	for idx := 0; idx < s.NumField(); idx++ {
+		fType := typeInfo.Field(idx)
+		if !s.Field(idx).CanSet() {
+			continue
+		}
+
+		names := []string{fType.Name}
+		for _, tag := range []string{"yaml", "json", "hcl"} {
+			if name, _, _ := strings.Cut(fType.Tag.Get(tag), ","); name != "" && name != "-" {
+				names = append(names, name)
+			}
+		}
+
+		for _, name := range names {
+			if strings.EqualFold(name, key) {
+				return s.Field(idx), fType, true
+			}
+		}
+	}
+
+	return reflect.Value{}, reflect.StructField{}, false
+}
+
+// structMeta is a structure metadata entity
+type structMeta struct {
+	envList         []string
+	indexedEnvs     []string
+	appendEnv       bool
+	flagName        string
+	flagShorthand   string
+	derivedFlagName string
+	fieldName       string
+	fieldPath       string
+	fieldValue      reflect.Value
+	defValue        *string
+	required        bool
+	requiredGroup   string
+	presentTrue     bool
+	secretRef       bool
+	validate        string
+	parseOptions
+}
+
+// parseOptions controls how raw values are parsed into a field
+type parseOptions struct {
+	// separator splits the items of slices and maps
+	separator string
+	// base is used for integers, 0 detects the base from the prefix (0x, 0o, 0b, 0)
+	base int
+	// csv splits slices honoring double-quotes
+	csv bool
+	// size parses integers with a unit suffix into bytes
+	size bool
+	// percent parses floats with a trailing "%" as percentage
+	percent bool
+	// char parses a single character into its code point (rune) or byte value
+	char bool
+	// typeHint is the concrete type allocated for interface{} values
+	typeHint reflect.Type
+}
+
+// typeHints maps the values of the env-type tag to their types
+var typeHints = map[string]reflect.Type{
+	"int":      reflect.TypeOf(0),
+	"float":    reflect.TypeOf(float64(0)),
+	"bool":     reflect.TypeOf(false),
+	"string":   reflect.TypeOf(""),
+	"duration": reflect.TypeOf(time.Duration(0)),
+}
+
+// isFieldValueZero determines if fieldValue empty or not
+func (sm *structMeta) isFieldValueZero() bool {
+	return isZero(sm.fieldValue)
+}
+
+// isFieldValueEmpty determines if fieldValue is zero or a non-nil but empty slice or map,
+// e.g. from "[]" in a file, which should get the default value as well
+func (sm *structMeta) isFieldValueEmpty() bool {
+	switch sm.fieldValue.Kind() {
+	case reflect.Slice, reflect.Map:
+		return sm.fieldValue.Len() == 0
+	default:
+		return sm.isFieldValueZero()
+	}
+}
+
+// readStructMetadata reads structure metadata (types, tags, etc.)
+func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
+	return readStructMetadataWithOptions(cfgRoot, ReadOptions{})
+}
+
+// readStructMetadataWithOptions reads structure metadata and applies the EnvPrefixSeparator of opts to the root
+func readStructMetadataWithOptions(cfgRoot interface{}, opts ReadOptions) ([]structMeta, error) {
+	type cfgNode struct {
+		Val        reflect.Value
+		Prefixes   []string
+		PrefixSep  string
+		Path       string
+		Separator  string
+		FlagPrefix string
+	}
+
+	cfgStack := []cfgNode{{reflect.ValueOf(cfgRoot), []string{""}, opts.EnvPrefixSeparator, "", DefaultSeparator, ""}}
+	metas := make([]structMeta, 0)
+
+	for i := 0; i < len(cfgStack); i++ {
+
+		s := cfgStack[i].Val
+		sPrefixes := cfgStack[i].Prefixes
+		sPrefixSep := cfgStack[i].PrefixSep
+		sPath := cfgStack[i].Path
+		sSeparator := cfgStack[i].Separator
+		sFlagPrefix := cfgStack[i].FlagPrefix
+
+		// unwrap pointer
+		if s.Kind() == reflect.Ptr {
+			s = s.Elem()
+		}
+
+		// process only structures
+		if s.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("wrong type %v", s.Kind())
+		}
+		typeInfo := s.Type()
+
+		// read tags
+		for idx := 0; idx < s.NumField(); idx++ {
+			fType := typeInfo.Field(idx)
 
 			var (
-				defValue  *string
-				flagName  string
-				separator string
+				defValue        *string
+				flagName        string
+				flagShorthand   string
+				derivedFlagName string
+				separator       string
+				base            int
 			)
 
-			// process nested structure
-			if fld := s.Field(idx); fld.Kind() == reflect.Struct {
-				prefix, _ := fType.Tag.Lookup(TagEnvPrefix)
-				cfgStack = append(cfgStack, cfgNode{fld.Addr().Interface(), sPrefix + prefix})
+			if sep, ok := fType.Tag.Lookup(TagEnvSeparator); ok {
+				separator = sep
+			} else {
+				separator = sSeparator
+			}
+
+			// process nested structure, fields of unexported structures can't be set except the
+			// exported fields of embedded ones, which are promoted to the parent
+			isNested := s.Field(idx).Kind() == reflect.Struct && !isSpecialType(fType.Type)
+			if fld := s.Field(idx); isNested && (fld.CanInterface() || fType.Anonymous) {
+				prefixSep := sPrefixSep
+				if sep, ok := fType.Tag.Lookup(TagEnvPrefixSeparator); ok {
+					prefixSep = sep
+				}
+
+				// every prefix of the list is combined with every prefix of the parent, in the order of the parent
+				prefixes := make([]string, 0, len(sPrefixes))
+				for _, parent := range sPrefixes {
+					for _, prefix := range strings.Split(fType.Tag.Get(TagEnvPrefix), DefaultSeparator) {
+						if prefix != "" && !strings.HasSuffix(prefix, prefixSep) {
+							prefix += prefixSep
+						}
+						prefixes = append(prefixes, parent+prefix)
+					}
+				}
+
+				// fields of embedded structures are promoted to the path of the parent
+				path, flagPrefix := sPath+fType.Name+".", sFlagPrefix+deriveFlagName(fType)+"-"
+				if fType.Anonymous {
+					path, flagPrefix = sPath, sFlagPrefix
+				}
+				cfgStack = append(cfgStack, cfgNode{fld, prefixes, prefixSep, path, separator, flagPrefix})
 			}
 
 			// check is the field value can be changed
@@ -356,41 +1384,128 @@ func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 				continue
 			}
 
+			envs, inlineDef, hasInlineDef := strings.Cut(fType.Tag.Get(TagEnv), ":")
 			if def, ok := fType.Tag.Lookup(TagEnvDefault); ok {
 				defValue = &def
+			} else if hasInlineDef {
+				defValue = &inlineDef
 			}
 
 			if flag, ok := fType.Tag.Lookup(TagFlagName); ok {
-				flagName = flag
+				flagName, flagShorthand, _ = strings.Cut(flag, DefaultSeparator)
+				if len(flagShorthand) > 1 {
+					return nil, fmt.Errorf("invalid flag shorthand %q on field %q, only a single character is allowed", flagShorthand, fType.Name)
+				}
 			}
 
-			if sep, ok := fType.Tag.Lookup(TagEnvSeparator); ok {
-				separator = sep
-			} else {
-				separator = DefaultSeparator
+			if b, ok := fType.Tag.Lookup(TagEnvBase); ok {
+				parsed, err := strconv.Atoi(b)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s %q on field %q: %w", TagEnvBase, b, fType.Name, err)
+				}
+				base = parsed
+			}
+
+			var typeHint reflect.Type
+			if hint, ok := fType.Tag.Lookup(TagEnvType); ok {
+				typeHint, ok = typeHints[hint]
+				if !ok {
+					return nil, fmt.Errorf("invalid %s %q on field %q", TagEnvType, hint, fType.Name)
+				}
+			}
+
+			size, _ := strconv.ParseBool(fType.Tag.Get(TagEnvSize))
+			if size && !isSizeType(fType.Type) {
+				return nil, fmt.Errorf("%s on field %q requires an integer field, got %s", TagEnvSize, fType.Name, fType.Type)
+			}
+
+			percent, _ := strconv.ParseBool(fType.Tag.Get(TagEnvPercent))
+			if percent && !isFloatType(fType.Type) {
+				return nil, fmt.Errorf("%s on field %q requires a float field, got %s", TagEnvPercent, fType.Name, fType.Type)
+			}
+
+			char, _ := strconv.ParseBool(fType.Tag.Get(TagEnvChar))
+			if char && fType.Type.Kind() != reflect.Int32 && fType.Type.Kind() != reflect.Uint8 {
+				return nil, fmt.Errorf("%s on field %q requires a rune or byte field, got %s", TagEnvChar, fType.Name, fType.Type)
+			}
+
+			csv, _ := strconv.ParseBool(fType.Tag.Get(TagEnvCSV))
+			if csv && utf8.RuneCountInString(separator) != 1 {
+				return nil, fmt.Errorf("%s on field %q requires a single-character separator, got %q", TagEnvCSV, fType.Name, separator)
 			}
 
 			_, required := fType.Tag.Lookup(TagEnvRequired)
+			requiredGroup := fType.Tag.Get(TagEnvRequiredGroup)
+
+			secretRef, _ := strconv.ParseBool(fType.Tag.Get(TagEnvSecretRef))
+
+			presentTrue, _ := strconv.ParseBool(fType.Tag.Get(TagEnvPresentTrue))
+			if presentTrue && fType.Type.Kind() != reflect.Bool {
+				return nil, fmt.Errorf("%s on field %q requires a bool field, got %s", TagEnvPresentTrue, fType.Name, fType.Type)
+			}
+
+			// nested structures are read by their fields, so only the fields get a derived flag
+			if !isNested {
+				derivedFlagName = sFlagPrefix + deriveFlagName(fType)
+			}
+
+			var indexedEnvs []string
+			if indexedEnv := fType.Tag.Get(TagEnvIndexed); indexedEnv != "" {
+				if fType.Type.Kind() != reflect.Slice {
+					return nil, fmt.Errorf("%s on field %q requires a slice field, got %s", TagEnvIndexed, fType.Name, fType.Type)
+				}
+				for _, prefix := range sPrefixes {
+					indexedEnvs = append(indexedEnvs, prefix+indexedEnv)
+				}
+			}
+
+			var appendEnv bool
+			switch merge := fType.Tag.Get(TagEnvMerge); merge {
+			case "", "replace":
+			case "append":
+				if fType.Type.Kind() != reflect.Slice {
+					return nil, fmt.Errorf("%s on field %q requires a slice field, got %s", TagEnvMerge, fType.Name, fType.Type)
+				}
+				appendEnv = true
+			default:
+				return nil, fmt.Errorf("invalid %s %q on field %q", TagEnvMerge, merge, fType.Name)
+			}
 
 			envList := make([]string, 0)
 
-			if envs, ok := fType.Tag.Lookup(TagEnv); ok && len(envs) != 0 {
-				envList = strings.Split(envs, DefaultSeparator)
-				if sPrefix != "" {
-					for i := range envList {
-						envList[i] = sPrefix + envList[i]
+			if len(envs) != 0 {
+				for _, prefix := range sPrefixes {
+					for _, env := range strings.Split(envs, DefaultSeparator) {
+						envList = append(envList, prefix+env)
 					}
 				}
 			}
 
 			metas = append(metas, structMeta{
-				envList:    envList,
-				flagName:   flagName,
-				fieldName:  s.Type().Field(idx).Name,
-				fieldValue: s.Field(idx),
-				defValue:   defValue,
-				separator:  separator,
-				required:   required,
+				envList:         envList,
+				indexedEnvs:     indexedEnvs,
+				appendEnv:       appendEnv,
+				flagName:        flagName,
+				flagShorthand:   flagShorthand,
+				derivedFlagName: derivedFlagName,
+				fieldName:       s.Type().Field(idx).Name,
+				fieldPath:       sPath + s.Type().Field(idx).Name,
+				fieldValue:      s.Field(idx),
+				defValue:        defValue,
+				required:        required,
+				requiredGroup:   requiredGroup,
+				presentTrue:     presentTrue,
+				secretRef:       secretRef,
+				validate:        fType.Tag.Get(TagValidate),
+				parseOptions: parseOptions{
+					separator: separator,
+					base:      base,
+					csv:       csv,
+					size:      size,
+					percent:   percent,
+					char:      char,
+					typeHint:  typeHint,
+				},
 			})
 		}
 
@@ -399,19 +1514,63 @@ func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 	return metas, nil
 }
 
+// deriveFlagName returns the yaml- or json-name of the field, or its kebab-cased name
+func deriveFlagName(fType reflect.StructField) string {
+	for _, tag := range []string{"yaml", "json"} {
+		if name, _, _ := strings.Cut(fType.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return strcase.ToKebab(fType.Name)
+}
+
 // readEnvVars reads environment variables to the provided configuration structure
-func readEnvVars(cfg interface{}, metaInfo []structMeta) error {
+func readEnvVars(cfg interface{}, metaInfo []structMeta, opts ReadOptions, sources map[string]Source) error {
 	for _, meta := range metaInfo {
 		var rawValue *string
+		source := SourceDefault
 
 		for _, env := range meta.envList {
-			if value, ok := os.LookupEnv(env); ok {
+			value, ok, err := lookupEnv(env, opts)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				if meta.presentTrue {
+					value = "true"
+				}
+				if meta.secretRef {
+					if value, err = resolveSecretRef(env, value, opts); err != nil {
+						return err
+					}
+				}
 				rawValue = &value
+				source = SourceEnv
 				break
 			}
 		}
 
-		if rawValue == nil && meta.isFieldValueZero() {
+		if rawValue == nil && len(meta.indexedEnvs) > 0 {
+			items, err := lookupIndexedEnvs(meta.indexedEnvs, opts)
+			if err != nil {
+				return err
+			}
+
+			if len(items) > 0 {
+				err := mergeEnvValue(meta, func(field reflect.Value) error {
+					return parseSliceItems(field, items, meta.parseOptions)
+				})
+				if err != nil {
+					return err
+				}
+				recordSource(sources, meta, SourceEnv)
+				continue
+			}
+		}
+
+		if rawValue == nil && meta.isFieldValueEmpty() {
 			rawValue = meta.defValue
 		}
 
@@ -419,29 +1578,267 @@ func readEnvVars(cfg interface{}, metaInfo []structMeta) error {
 			continue
 		}
 
-		if err := parseValue(meta.fieldValue, *rawValue, meta.separator); err != nil {
+		if source == SourceEnv {
+			err := mergeEnvValue(meta, func(field reflect.Value) error {
+				return parseValue(field, *rawValue, meta.parseOptions)
+			})
+			if err != nil {
+				return err
+			}
+		} else if err := parseValue(meta.fieldValue, *rawValue, meta.parseOptions); err != nil {
 			return err
 		}
+		recordSource(sources, meta, source)
 	}
 
 	return nil
 }
 
+// mergeEnvValue parses the value of an environment variable with parse into the field,
+// or into a new slice which is appended to the field for env-merge "append"
+func mergeEnvValue(meta structMeta, parse func(reflect.Value) error) error {
+	if !meta.appendEnv {
+		return parse(meta.fieldValue)
+	}
+
+	items := reflect.New(meta.fieldValue.Type()).Elem()
+	if err := parse(items); err != nil {
+		return err
+	}
+
+	meta.fieldValue.Set(reflect.AppendSlice(meta.fieldValue, items))
+	return nil
+}
+
+// lookupIndexedEnvs returns the values of the environment variables name_0, name_1, ... up to the first missing index
+// for the first of the names which has at least one index set
+func lookupIndexedEnvs(names []string, opts ReadOptions) ([]string, error) {
+	items := []string{}
+	for _, name := range names {
+		for i := 0; ; i++ {
+			value, ok, err := lookupEnv(name+"_"+strconv.Itoa(i), opts)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				break
+			}
+			items = append(items, value)
+		}
+
+		if len(items) > 0 {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+// lookupEnv returns the value of the environment variable env, falling back to the content of the file
+// named by env with a "_FILE" suffix if enabled in opts
+func lookupEnv(env string, opts ReadOptions) (string, bool, error) {
+	if opts.SkipEnv {
+		return "", false, nil
+	}
+
+	if value, ok := os.LookupEnv(env); ok && (value != "" || !opts.SkipEmptyEnv) {
+		return value, true, nil
+	}
+
+	if !opts.ReadEnvFiles {
+		return "", false, nil
+	}
+
+	path, ok := os.LookupEnv(env + "_FILE")
+	if !ok {
+		return "", false, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("reading %s_FILE: %w", env, err)
+	}
+
+	return strings.TrimSpace(string(content)), true, nil
+}
+
+// TypeParserFunc parses the raw value of a field of the type it is registered for
+type TypeParserFunc func(value string) (interface{}, error)
+
+var (
+	typeParsersMu sync.RWMutex
+	typeParsers   = map[reflect.Type]TypeParserFunc{}
+)
+
+// RegisterTypeParser registers a function which parses the raw values of all fields of type t, e.g. for
+// third-party types like *regexp.Regexp which can't implement Setter. It takes precedence over Setter and the
+// built-in parsing and also applies to the items of slices and maps. The returned value must be assignable to t.
+//
+// Example:
+//
+//	 config.RegisterTypeParser(reflect.TypeOf(&regexp.Regexp{}), func(s string) (interface{}, error) {
+//	     return regexp.Compile(s)
+//	 })
+func RegisterTypeParser(t reflect.Type, fn func(string) (interface{}, error)) {
+	typeParsersMu.Lock()
+	defer typeParsersMu.Unlock()
+	typeParsers[t] = fn
+}
+
+// lookupTypeParser returns the parser registered for the type or nil
+func lookupTypeParser(t reflect.Type) TypeParserFunc {
+	typeParsersMu.RLock()
+	defer typeParsersMu.RUnlock()
+	return typeParsers[t]
+}
+
+// Pair is a key-value item, a []Pair field is parsed like a map from "a:1,b:2" but keeps the order of the items
+type Pair struct {
+	Key   string
+	Value string
+}
+
+var (
+	setterType = reflect.TypeOf((*Setter)(nil)).Elem()
+	pairType   = reflect.TypeOf(Pair{})
+	ipType     = reflect.TypeOf(net.IP{})
+	ipNetType  = reflect.TypeOf(net.IPNet{})
+	urlType    = reflect.TypeOf(url.URL{})
+)
+
+// isSpecialType reports whether values of the type are parsed by parseSpecialType
+func isSpecialType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t == ipType || t == ipNetType || t == urlType
+}
+
+// parseSpecialType parses net.IP, net.IPNet and url.URL values (or pointers to them)
+// and reports whether the field has one of these types
+func parseSpecialType(field reflect.Value, value string) (bool, error) {
+	if !isSpecialType(field.Type()) {
+		return false, nil
+	}
+
+	baseType := field.Type()
+	if baseType.Kind() == reflect.Ptr {
+		baseType = baseType.Elem()
+	}
+
+	var parsed interface{}
+	switch baseType {
+	case ipType:
+		ip := net.ParseIP(strings.TrimSpace(value))
+		if ip == nil {
+			return true, fmt.Errorf("invalid IP address %q", value)
+		}
+		parsed = ip
+	case ipNetType:
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(value))
+		if err != nil {
+			return true, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		parsed = ipNet
+	default:
+		u, err := url.Parse(strings.TrimSpace(value))
+		if err != nil {
+			return true, fmt.Errorf("invalid URL %q: %w", value, err)
+		}
+		parsed = u
+	}
+
+	parsedValue := reflect.ValueOf(parsed)
+	if parsedValue.Kind() == reflect.Ptr && field.Kind() != reflect.Ptr {
+		parsedValue = parsedValue.Elem()
+	} else if parsedValue.Kind() != reflect.Ptr && field.Kind() == reflect.Ptr {
+		ptr := reflect.New(baseType)
+		ptr.Elem().Set(parsedValue)
+		parsedValue = ptr
+	}
+	field.Set(parsedValue)
+	return true, nil
+}
+
+// callSetter passes value to the Setter of the field and reports whether the field or its pointer implements it.
+// Nil pointers are allocated with reflect.New first, so elements of e.g. []*MyField can be set.
+// Slice elements and the map values created by parseMap are addressable, so pointer-receivers work for them.
+func callSetter(field reflect.Value, value string) (bool, error) {
+	if !field.CanInterface() {
+		return false, nil
+	}
+
+	valueType := field.Type()
+	if valueType.Kind() == reflect.Ptr && field.IsNil() && valueType.Implements(setterType) {
+		ptr := reflect.New(valueType.Elem())
+		if err := ptr.Interface().(Setter).SetValue(value); err != nil {
+			return true, err
+		}
+		field.Set(ptr)
+		return true, nil
+	}
+
+	if cs, ok := field.Interface().(Setter); ok {
+		return true, cs.SetValue(value)
+	}
+
+	if !reflect.PtrTo(valueType).Implements(setterType) {
+		return false, nil
+	}
+
+	if !field.CanAddr() {
+		return true, fmt.Errorf("cannot call SetValue of %s on a value which is not addressable", valueType)
+	}
+
+	return true, field.Addr().Interface().(Setter).SetValue(value)
+}
+
 // parseValue parses value into the corresponding field.
 // In case of maps and slices it uses provided separator to split raw value string
-func parseValue(field reflect.Value, value, sep string) error {
+func parseValue(field reflect.Value, value string, opts parseOptions) error {
 	// TODO: simplify recursion
 
-	if field.CanInterface() {
-		if cs, ok := field.Interface().(Setter); ok {
-			return cs.SetValue(value)
-		} else if csp, ok := field.Addr().Interface().(Setter); ok {
-			return csp.SetValue(value)
+	if fn := lookupTypeParser(field.Type()); fn != nil {
+		parsed, err := fn(value)
+		if err != nil {
+			return err
+		}
+
+		parsedValue := reflect.ValueOf(parsed)
+		if !parsedValue.IsValid() {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
 		}
+
+		if !parsedValue.Type().AssignableTo(field.Type()) {
+			return fmt.Errorf("type parser for %s returned %s", field.Type(), parsedValue.Type())
+		}
+		field.Set(parsedValue)
+		return nil
+	}
+
+	if ok, err := callSetter(field, value); ok {
+		return err
+	}
+
+	if ok, err := parseSpecialType(field, value); ok {
+		return err
 	}
 
 	valueType := field.Type()
 
+	// parse "key:value" into a pair, used for ordered []Pair
+	if valueType == pairType {
+		kvPair := strings.SplitN(value, ":", 2)
+		if len(kvPair) != 2 {
+			return fmt.Errorf("invalid pair item: %q", value)
+		}
+		field.Set(reflect.ValueOf(Pair{Key: kvPair[0], Value: kvPair[1]}))
+		return nil
+	}
+
 	switch valueType.Kind() {
 	// parse string value
 	case reflect.String:
@@ -449,7 +1846,7 @@ func parseValue(field reflect.Value, value, sep string) error {
 
 	// parse boolean value
 	case reflect.Bool:
-		b, err := strconv.ParseBool(value)
+		b, err := parseBool(value)
 		if err != nil {
 			return err
 		}
@@ -457,8 +1854,41 @@ func parseValue(field reflect.Value, value, sep string) error {
 
 	// parse integer (or time) value
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// parse duration
+		if valueType == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+
+		// parse size with unit suffix
+		if opts.size {
+			size, err := parseSize(value)
+			if err != nil {
+				return err
+			}
+			if size > math.MaxInt64 || field.OverflowInt(int64(size)) {
+				return fmt.Errorf("size %q overflows %s", value, valueType)
+			}
+			field.SetInt(int64(size))
+			return nil
+		}
+
+		// parse single character into its code point
+		if opts.char {
+			r, size := utf8.DecodeRuneInString(value)
+			if r == utf8.RuneError || size != len(value) {
+				return fmt.Errorf("invalid character %q, exactly one character is required", value)
+			}
+			field.SetInt(int64(r))
+			return nil
+		}
+
 		// parse regular integer
-		number, err := strconv.ParseInt(value, 0, valueType.Bits())
+		number, err := strconv.ParseInt(value, opts.base, valueType.Bits())
 		if err != nil {
 			return err
 		}
@@ -466,7 +1896,27 @@ func parseValue(field reflect.Value, value, sep string) error {
 
 	// parse unsigned integer value
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		number, err := strconv.ParseUint(value, 0, valueType.Bits())
+		if opts.size {
+			size, err := parseSize(value)
+			if err != nil {
+				return err
+			}
+			if field.OverflowUint(size) {
+				return fmt.Errorf("size %q overflows %s", value, valueType)
+			}
+			field.SetUint(size)
+			return nil
+		}
+
+		if opts.char {
+			if len(value) != 1 {
+				return fmt.Errorf("invalid byte %q, exactly one single-byte character is required", value)
+			}
+			field.SetUint(uint64(value[0]))
+			return nil
+		}
+
+		number, err := strconv.ParseUint(value, opts.base, valueType.Bits())
 		if err != nil {
 			return err
 		}
@@ -474,6 +1924,15 @@ func parseValue(field reflect.Value, value, sep string) error {
 
 	// parse floating point value
 	case reflect.Float32, reflect.Float64:
+		if opts.percent && strings.HasSuffix(strings.TrimSpace(value), "%") {
+			number, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), valueType.Bits())
+			if err != nil {
+				return err
+			}
+			field.SetFloat(number / 100)
+			return nil
+		}
+
 		number, err := strconv.ParseFloat(value, valueType.Bits())
 		if err != nil {
 			return err
@@ -482,7 +1941,7 @@ func parseValue(field reflect.Value, value, sep string) error {
 
 	// parse sliced value
 	case reflect.Slice:
-		sliceValue, err := parseSlice(valueType, value, sep)
+		sliceValue, err := parseSlice(valueType, value, opts)
 		if err != nil {
 			return err
 		}
@@ -491,13 +1950,26 @@ func parseValue(field reflect.Value, value, sep string) error {
 
 	// parse mapped value
 	case reflect.Map:
-		mapValue, err := parseMap(valueType, value, sep)
+		mapValue, err := parseMap(valueType, value, opts)
 		if err != nil {
 			return err
 		}
 
 		field.Set(*mapValue)
 
+	// parse value of the hinted type into interface
+	case reflect.Interface:
+		if opts.typeHint == nil || !opts.typeHint.Implements(valueType) {
+			return fmt.Errorf("unsupported type %s, an %s tag is required", valueType, TagEnvType)
+		}
+
+		hinted := reflect.New(opts.typeHint).Elem()
+		if err := parseValue(hinted, value, opts); err != nil {
+			return err
+		}
+
+		field.Set(hinted)
+
 	default:
 		return fmt.Errorf("unsupported type %s.%s", valueType.PkgPath(), valueType.Name())
 	}
@@ -505,19 +1977,104 @@ func parseValue(field reflect.Value, value, sep string) error {
 	return nil
 }
 
+// parseBool parses yes/no, on/off and enabled/disabled case-insensitive like YAML does,
+// all other values are parsed with strconv.ParseBool
+func parseBool(value string) (bool, error) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "yes", "on", "enabled":
+		return true, nil
+	case "no", "off", "disabled":
+		return false, nil
+	default:
+		return strconv.ParseBool(value)
+	}
+}
+
+// sizeUnits maps the supported size suffixes to their number of bytes, matched case-insensitive
+var sizeUnits = map[string]uint64{
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+}
+
+// parseSize parses a size like "10MB" or "512KiB" into a number of bytes, a plain integer is taken as bytes
+func parseSize(value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+	idx := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+	if idx == -1 {
+		return strconv.ParseUint(value, 10, 64)
+	}
+
+	number, err := strconv.ParseUint(value[:idx], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", value, err)
+	}
+
+	unit := strings.ToLower(strings.TrimSpace(value[idx:]))
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", value, value[idx:])
+	}
+
+	if number > math.MaxUint64/multiplier {
+		return 0, fmt.Errorf("size %q overflows uint64", value)
+	}
+
+	return number * multiplier, nil
+}
+
+// isSizeType reports whether the type or its element type is an integer which can hold a size
+func isSizeType(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		// byte-slices are parsed from the raw string
+		return false
+	}
+
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isFloatType reports whether the type or its element type is a float
+func isFloatType(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+}
+
 // parseSlice parses value into a slice of given type
-func parseSlice(valueType reflect.Type, value string, sep string) (*reflect.Value, error) {
+func parseSlice(valueType reflect.Type, value string, opts parseOptions) (*reflect.Value, error) {
 	sliceValue := reflect.MakeSlice(valueType, 0, 0)
 	if valueType.Elem().Kind() == reflect.Uint8 {
 		sliceValue = reflect.ValueOf([]byte(value))
 	} else if len(strings.TrimSpace(value)) != 0 {
-		value = strings.Replace(value, "[", "", 1)
-		value = strings.Replace(value, "]", "", 1)
-		values := strings.Split(value, sep)
+		values, err := splitSlice(value, opts)
+		if err != nil {
+			return nil, err
+		}
+
 		sliceValue = reflect.MakeSlice(valueType, len(values), len(values))
 
 		for i, val := range values {
-			if err := parseValue(sliceValue.Index(i), val, sep); err != nil {
+			if err := parseValue(sliceValue.Index(i), val, opts); err != nil {
 				return nil, err
 			}
 		}
@@ -525,23 +2082,85 @@ func parseSlice(valueType reflect.Type, value string, sep string) (*reflect.Valu
 	return &sliceValue, nil
 }
 
+// splitSlice splits the raw value of a slice into its items
+func splitSlice(value string, opts parseOptions) ([]string, error) {
+	if !opts.csv {
+		value = strings.Replace(value, "[", "", 1)
+		value = strings.Replace(value, "]", "", 1)
+		return splitEscaped(value, opts.separator), nil
+	}
+
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+	}
+
+	reader := csv.NewReader(strings.NewReader(value))
+	reader.Comma, _ = utf8.DecodeRuneInString(opts.separator)
+	values, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv value %q: %w", value, err)
+	}
+
+	return values, nil
+}
+
+// splitEscaped splits value at sep, a separator preceded by a backslash is kept as part of the item,
+// e.g. `a,b\,c` is split into "a" and "b,c". Other backslashes are kept as they are
+func splitEscaped(value, sep string) []string {
+	escaped := `\` + sep
+	if sep == "" || !strings.Contains(value, escaped) {
+		return strings.Split(value, sep)
+	}
+
+	items := make([]string, 0)
+	var item strings.Builder
+	for len(value) > 0 {
+		switch {
+		case strings.HasPrefix(value, escaped):
+			item.WriteString(sep)
+			value = value[len(escaped):]
+		case strings.HasPrefix(value, sep):
+			items = append(items, item.String())
+			item.Reset()
+			value = value[len(sep):]
+		default:
+			item.WriteByte(value[0])
+			value = value[1:]
+		}
+	}
+
+	return append(items, item.String())
+}
+
 // parseMap parses value into a map of given type
-func parseMap(valueType reflect.Type, value string, sep string) (*reflect.Value, error) {
+func parseMap(valueType reflect.Type, value string, opts parseOptions) (*reflect.Value, error) {
+	if !isMapKeyType(valueType.Key()) {
+		return nil, fmt.Errorf("unsupported map key type %s", valueType.Key())
+	}
+
 	mapValue := reflect.MakeMap(valueType)
 	if len(strings.TrimSpace(value)) != 0 {
-		pairs := strings.Split(value, sep)
+		pairs := strings.Split(value, opts.separator)
 		for _, pair := range pairs {
 			kvPair := strings.SplitN(pair, ":", 2)
 			if len(kvPair) != 2 {
 				return nil, fmt.Errorf("invalid map item: %q", pair)
 			}
 			k := reflect.New(valueType.Key()).Elem()
-			err := parseValue(k, kvPair[0], sep)
+			err := parseValue(k, kvPair[0], opts)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("invalid map key %q: %w", kvPair[0], err)
 			}
 			v := reflect.New(valueType.Elem()).Elem()
-			err = parseValue(v, kvPair[1], sep)
+			if valueType.Elem() == reflect.TypeOf(time.Duration(0)) && lookupTypeParser(valueType.Elem()) == nil {
+				// compact duration maps like "a:1s,b:100" take bare integers as nanoseconds
+				if number, err := strconv.ParseInt(kvPair[1], 10, 64); err == nil {
+					v.SetInt(number)
+					mapValue.SetMapIndex(k, v)
+					continue
+				}
+			}
+			err = parseValue(v, kvPair[1], opts)
 			if err != nil {
 				return nil, err
 			}
@@ -551,6 +2170,24 @@ func parseMap(valueType reflect.Type, value string, sep string) (*reflect.Value,
 	return &mapValue, nil
 }
 
+// isMapKeyType reports whether map keys of the type can be parsed, these are strings, bools,
+// numbers, types implementing Setter and interfaces with an env-type hint
+func isMapKeyType(keyType reflect.Type) bool {
+	if keyType.Implements(setterType) || reflect.PtrTo(keyType).Implements(setterType) {
+		return true
+	}
+
+	switch keyType.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
 // isZero is a backport of reflect.Value.IsZero()
 func isZero(v reflect.Value) bool {
 	switch v.Kind() {
@@ -2,10 +2,17 @@ package libstandard
 
 import (
 	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -318,6 +325,128 @@ func TestReadFromFlags(t *testing.T) {
 	}
 }
 
+func TestReadFromSliceFlags(t *testing.T) {
+	type config struct {
+		Names []string `flag:"names" env-separator:";"`
+		Ports []int    `flag:"ports"`
+		Tags  []string `flag:"tags"`
+	}
+
+	flagSet := &pflag.FlagSet{}
+	flagSet.StringSlice("names", []string{}, "")
+	flagSet.IntSlice("ports", []int{80}, "")
+	flagSet.StringSlice("tags", []string{"default"}, "")
+	assert.NoError(t, flagSet.Parse([]string{"--names", `a,"b,c"`, "--names", "d", "--ports", "8080,9090"}))
+
+	var cfg config
+	err := ReadFromFlags(&cfg, flagSet)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Names: []string{"a", "b,c", "d"},
+		Ports: []int{8080, 9090},
+		Tags:  []string{"default"},
+	}, cfg)
+
+	flagSet = &pflag.FlagSet{}
+	flagSet.StringSlice("ports", []string{}, "")
+	assert.NoError(t, flagSet.Parse([]string{"--ports", "80,http"}))
+	assert.Error(t, ReadFromFlags(&config{}, flagSet))
+}
+
+func TestReadFromUnsetSliceFlags(t *testing.T) {
+	type config struct {
+		Items []string `flag:"items" env-required:"true"`
+		Tags  []string `flag:"tags" env-default:"a,b"`
+	}
+
+	flagSet := &pflag.FlagSet{}
+	flagSet.StringSlice("items", []string{}, "")
+	flagSet.StringSlice("tags", nil, "")
+	assert.NoError(t, flagSet.Parse([]string{}))
+
+	var cfg config
+	err := ReadFromFlags(&cfg, flagSet)
+	assert.EqualError(t, err, `field "Items" is required but the value is not provided`)
+	assert.Nil(t, cfg.Items)
+	assert.Equal(t, []string{"a", "b"}, cfg.Tags)
+
+	cfg = config{}
+	sources, err := ReadWithSources(&cfg, flagSet, "", DefaultFileConfig{})
+	assert.Error(t, err)
+	assert.Equal(t, SourceNone, sources["Items"])
+
+	assert.NoError(t, flagSet.Parse([]string{"--items", "x"}))
+	cfg = config{}
+	err = ReadFromFlags(&cfg, flagSet)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x"}, cfg.Items)
+}
+
+func TestReadFromFlagsWithBase(t *testing.T) {
+	type config struct {
+		Typed   int   `flag:"typed" env-base:"16"`
+		Default int   `flag:"default" env-base:"16"`
+		Str     int   `flag:"str" env-base:"16"`
+		Ints    []int `flag:"ints" env-base:"16"`
+		Strs    []int `flag:"strs" env-base:"16"`
+		Env     int   `flag:"env" env:"TEST_ENV_BASE" env-base:"16"`
+	}
+
+	flagSet := &pflag.FlagSet{}
+	flagSet.Int("typed", 0, "")
+	flagSet.Int("default", 16, "")
+	flagSet.String("str", "", "")
+	flagSet.IntSlice("ints", nil, "")
+	flagSet.StringSlice("strs", nil, "")
+	flagSet.Int("env", 0, "")
+	assert.NoError(t, flagSet.Parse([]string{"--typed", "10", "--str", "ff", "--ints", "10,20", "--strs", "a,10"}))
+
+	os.Setenv("TEST_ENV_BASE", "ff")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromFlags(&cfg, flagSet)
+	assert.NoError(t, err)
+	assert.Equal(t, config{Typed: 10, Default: 16, Str: 255, Ints: []int{10, 20}, Strs: []int{10, 16}, Env: 255}, cfg)
+}
+
+func TestReadFromFlagSets(t *testing.T) {
+	type config struct {
+		Host    string `flag:"host"`
+		Port    int    `flag:"port"`
+		Verbose bool   `flag:"verbose"`
+		Name    string `flag:"name"`
+	}
+
+	first := &pflag.FlagSet{}
+	first.StringP("host", "h", "localhost", "")
+	first.Int("port", 80, "")
+	first.BoolP("verbose", "v", false, "")
+	assert.NoError(t, first.Parse([]string{"--host", "first", "--port", "8080", "-v"}))
+
+	second := &pflag.FlagSet{}
+	second.StringP("host", "o", "", "")
+	second.Int("port", 443, "")
+	second.StringP("name", "v", "app", "")
+	assert.NoError(t, second.Parse([]string{"--host", "second"}))
+
+	var cfg config
+	err := ReadFromFlagSets(&cfg, first, nil, second)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Host:    "second",
+		Port:    8080,
+		Verbose: true,
+		Name:    "app",
+	}, cfg)
+
+	cfg = config{}
+	err = ReadFromFlagSets(&cfg, second, first)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", cfg.Host)
+	assert.Equal(t, 8080, cfg.Port)
+}
+
 type flagSetting struct {
 	defaultValue string
 	value        string
@@ -498,6 +627,42 @@ array: [1, 2, 3]`,
 			wantErr: false,
 		},
 
+		{
+			name: "ini",
+			file: `
+; comment
+number = 1
+float = 2.3
+string = "test"
+boolean = true
+array = 1,2,3
+
+[object]
+one = 1
+two = 2`,
+			ext:     "ini",
+			want:    &wantConfig,
+			wantErr: false,
+		},
+
+		{
+			name: "hcl",
+			file: `
+number  = 1
+float   = 2.3
+string  = "test"
+boolean = true
+array   = [1, 2, 3]
+
+object {
+  one = 1
+  two = 2
+}`,
+			ext:     "hcl",
+			want:    &wantConfig,
+			wantErr: false,
+		},
+
 		{
 			name:    "unknown",
 			file:    "-",
@@ -539,7 +704,7 @@ array: [1, 2, 3]`,
 	}
 
 	t.Run("invalid path", func(t *testing.T) {
-		err := parseFile("invalid file path", nil)
+		err := parseFile("invalid file path", nil, ReadOptions{})
 		if err == nil {
 			t.Error("expected error for invalid file path")
 		}
@@ -640,6 +805,84 @@ two: 2`,
 	}
 }
 
+func TestFindDefaultFile(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(second, "mycfg.yaml"), []byte("one: 1"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(second, "mycfg.json"), []byte(`{"one": 1}`), 0600))
+
+	assert.Equal(t, filepath.Join(second, "mycfg.json"), FindDefaultFile(DefaultFileConfig{Name: "mycfg", Extensions: []string{"json", "yaml"}, Paths: []string{first, second}}))
+	assert.Equal(t, filepath.Join(second, "mycfg.yaml"), FindDefaultFile(DefaultFileConfig{Name: "mycfg", Extensions: []string{"yml", "yaml"}, Paths: []string{second}}))
+	assert.Equal(t, "", FindDefaultFile(DefaultFileConfig{Name: "mycfg", Extensions: []string{"yaml"}, Paths: []string{first}}))
+	assert.Equal(t, "", FindDefaultFile(DefaultFileConfig{Name: "other", Extensions: []string{"yaml"}, Paths: []string{second}}))
+	assert.Equal(t, "", FindDefaultFile(DefaultFileConfig{}))
+}
+
+func TestReadWithConfigFileEnv(t *testing.T) {
+	type config struct {
+		Host string `yaml:"host"`
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "env.yaml"), []byte("host: env.local"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "custom.yaml"), []byte("host: custom.local"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mycfg.yaml"), []byte("host: default.local"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "explicit.yaml"), []byte("host: explicit.local"), 0600))
+	defaultCfg := DefaultFileConfig{Name: "mycfg", Extensions: []string{"yaml"}, Paths: []string{dir}}
+
+	os.Setenv(ConfigFileEnv, filepath.Join(dir, "env.yaml"))
+	os.Setenv("MYAPP_CONFIG", filepath.Join(dir, "custom.yaml"))
+	defer os.Clearenv()
+
+	var cfg config
+	assert.NoError(t, Read(&cfg, nil, "", defaultCfg))
+	assert.Equal(t, "default.local", cfg.Host)
+
+	cfg = config{}
+	assert.NoError(t, ReadWithOptions(&cfg, nil, "", defaultCfg, ReadOptions{ConfigFileEnv: ConfigFileEnv}))
+	assert.Equal(t, "env.local", cfg.Host)
+
+	cfg = config{}
+	assert.NoError(t, ReadWithOptions(&cfg, nil, filepath.Join(dir, "explicit.yaml"), defaultCfg, ReadOptions{ConfigFileEnv: ConfigFileEnv}))
+	assert.Equal(t, "explicit.local", cfg.Host)
+
+	cfg = config{}
+	assert.NoError(t, ReadWithOptions(&cfg, nil, "", defaultCfg, ReadOptions{ConfigFileEnv: "MYAPP_CONFIG"}))
+	assert.Equal(t, "custom.local", cfg.Host)
+
+	cfg = config{}
+	assert.NoError(t, ReadWithOptions(&cfg, nil, "", defaultCfg, ReadOptions{ConfigFileEnv: ConfigFileEnv, SkipEnv: true}))
+	assert.Equal(t, "default.local", cfg.Host)
+
+	os.Setenv(ConfigFileEnv, filepath.Join(dir, "missing.yaml"))
+	assert.NoError(t, Read(&config{}, nil, "", defaultCfg))
+	assert.Error(t, ReadWithOptions(&config{}, nil, "", defaultCfg, ReadOptions{ConfigFileEnv: ConfigFileEnv}))
+}
+
+func TestReadFromEnvIgnoresConfigFileEnv(t *testing.T) {
+	type config struct {
+		Host string `yaml:"host" env:"TEST_HOST"`
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "env.yaml"), []byte("host: env.local"), 0600))
+
+	os.Setenv(ConfigFileEnv, filepath.Join(dir, "env.yaml"))
+	defer os.Clearenv()
+
+	var cfg config
+	assert.NoError(t, ReadFromEnv(&cfg))
+	assert.Equal(t, "", cfg.Host)
+
+	assert.NoError(t, ReadFromEnvWithPrefix(&cfg, "APP_"))
+	assert.Equal(t, "", cfg.Host)
+
+	os.Setenv(ConfigFileEnv, filepath.Join(dir, "missing.yaml"))
+	os.Setenv("TEST_HOST", "from-env")
+	assert.NoError(t, ReadFromEnv(&cfg))
+	assert.Equal(t, "from-env", cfg.Host)
+}
+
 func TestReadFromFileWithEnvs(t *testing.T) {
 	type config struct {
 		Number    int64  `yaml:"number" env:"TEST_NUMBER" env-default:"1"`
@@ -779,3 +1022,2204 @@ func TestAddConfigFlag(t *testing.T) {
 	AddConfigFlag(cmd)
 	assert.NotNil(t, cmd.PersistentFlags().Lookup(Config))
 }
+
+func TestReload(t *testing.T) {
+	type config struct {
+		Host string `yaml:"host" env-default:"localhost"`
+		Port int    `yaml:"port" env-default:"8080"`
+	}
+
+	tmpFile, err := os.CreateTemp(os.TempDir(), "*.yaml")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	err = os.WriteFile(tmpFile.Name(), []byte("host: example.com\nport: 9090\n"), 0600)
+	assert.NoError(t, err)
+
+	var cfg config
+	err = ReadFromFile(&cfg, tmpFile.Name(), DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "example.com", Port: 9090}, cfg)
+
+	err = os.WriteFile(tmpFile.Name(), []byte("host: example.org\n"), 0600)
+	assert.NoError(t, err)
+
+	err = Reload(&cfg, nil, tmpFile.Name(), DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "example.org", Port: 8080}, cfg)
+
+	err = os.WriteFile(tmpFile.Name(), []byte("port: invalid\n"), 0600)
+	assert.NoError(t, err)
+
+	err = Reload(&cfg, nil, tmpFile.Name(), DefaultFileConfig{})
+	assert.Error(t, err)
+	assert.Equal(t, config{Host: "example.org", Port: 8080}, cfg)
+
+	err = Reload(config{}, nil, tmpFile.Name(), DefaultFileConfig{})
+	assert.Error(t, err)
+}
+
+func TestCheckConfig(t *testing.T) {
+	type config struct {
+		Host string `yaml:"host" env-required:"true"`
+		Port int    `yaml:"port" validate:"min=1,max=65535"`
+	}
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	assert.NoError(t, os.WriteFile(valid, []byte("host: example.com\nport: 8080\n"), 0600))
+	invalid := filepath.Join(dir, "invalid.yaml")
+	assert.NoError(t, os.WriteFile(invalid, []byte("host: example.com\nport: 70000\n"), 0600))
+	missing := filepath.Join(dir, "missing.yaml")
+	assert.NoError(t, os.WriteFile(missing, []byte("port: 8080\n"), 0600))
+
+	cfg := config{Host: "untouched"}
+	assert.NoError(t, CheckConfig(&cfg, nil, valid, DefaultFileConfig{}))
+	assert.Equal(t, config{Host: "untouched"}, cfg)
+
+	err := CheckConfig(&cfg, nil, invalid, DefaultFileConfig{})
+	assert.EqualError(t, err, `field "Port" is invalid: value 70000 is greater than max 65535`)
+
+	assert.NoError(t, CheckConfig(&cfg, nil, missing, DefaultFileConfig{}))
+	assert.Equal(t, config{Host: "untouched"}, cfg)
+
+	err = CheckConfig(&config{}, nil, missing, DefaultFileConfig{})
+	assert.EqualError(t, err, `field "Host" is required but the value is not provided`)
+
+	type preset struct {
+		Tags []string `yaml:"tags" validate:"min=1"`
+	}
+	empty := filepath.Join(dir, "empty.yaml")
+	assert.NoError(t, os.WriteFile(empty, []byte("{}\n"), 0600))
+
+	presetCfg := preset{Tags: []string{"a"}}
+	assert.NoError(t, CheckConfig(&presetCfg, nil, empty, DefaultFileConfig{}))
+	assert.Error(t, CheckConfig(&preset{}, nil, empty, DefaultFileConfig{}))
+
+	presetCfg = preset{Tags: []string{"a"}}
+	assert.NoError(t, CheckConfig(&presetCfg, nil, valid, DefaultFileConfig{}))
+	assert.Equal(t, []string{"a"}, presetCfg.Tags)
+
+	assert.Error(t, CheckConfig(config{}, nil, valid, DefaultFileConfig{}))
+}
+
+func TestApplyOverrides(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+
+	type config struct {
+		Name     string   `env:"NAME"`
+		Tags     []string `env:"TAGS"`
+		Database database `env-prefix:"DB_"`
+	}
+
+	cfg := config{Name: "app", Database: database{Host: "localhost", Port: 5432}}
+	err := ApplyOverrides(&cfg, []string{"NAME=other", "TAGS=a,b", "Database.Port=1234", "DB_HOST=db=1"})
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Name:     "other",
+		Tags:     []string{"a", "b"},
+		Database: database{Host: "db=1", Port: 1234},
+	}, cfg)
+
+	err = ApplyOverrides(&cfg, []string{"Database.Port=1", "DB_PORT=2"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cfg.Database.Port)
+
+	err = ApplyOverrides(&cfg, []string{"Unknown=1"})
+	assert.EqualError(t, err, `override for unknown field "Unknown"`)
+
+	err = ApplyOverrides(&cfg, []string{"NAME"})
+	assert.EqualError(t, err, `invalid override "NAME", expected key=value`)
+
+	err = ApplyOverrides(&cfg, []string{"DB_PORT=abc"})
+	assert.ErrorContains(t, err, `invalid override of field "Database.Port"`)
+}
+
+func TestRegisterTypeParser(t *testing.T) {
+	type config struct {
+		Pattern  *regexp.Regexp   `env:"TEST_PATTERN" flag:"pattern"`
+		Patterns []*regexp.Regexp `env:"TEST_PATTERNS"`
+	}
+
+	regexpType := reflect.TypeOf(&regexp.Regexp{})
+	defer func() {
+		typeParsersMu.Lock()
+		delete(typeParsers, regexpType)
+		typeParsersMu.Unlock()
+	}()
+
+	RegisterTypeParser(regexpType, func(s string) (interface{}, error) {
+		return regexp.Compile(s)
+	})
+
+	os.Setenv("TEST_PATTERN", "^a+$")
+	os.Setenv("TEST_PATTERNS", "^b$,c")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "^a+$", cfg.Pattern.String())
+	assert.True(t, cfg.Pattern.MatchString("aaa"))
+	assert.Len(t, cfg.Patterns, 2)
+	assert.Equal(t, "c", cfg.Patterns[1].String())
+
+	os.Clearenv()
+	flagSet := &pflag.FlagSet{}
+	flagSet.String("pattern", "", "")
+	assert.NoError(t, flagSet.Set("pattern", "^d$"))
+
+	cfg = config{}
+	err = ReadFromFlags(&cfg, flagSet)
+	assert.NoError(t, err)
+	assert.True(t, cfg.Pattern.MatchString("d"))
+
+	assert.NoError(t, flagSet.Set("pattern", "(["))
+	err = ReadFromFlags(&config{}, flagSet)
+	assert.Error(t, err)
+
+	RegisterTypeParser(regexpType, func(s string) (interface{}, error) {
+		return s, nil
+	})
+	err = ReadFromFlags(&config{}, flagSet)
+	assert.EqualError(t, err, "type parser for *regexp.Regexp returned string")
+}
+
+func TestRegisterParser(t *testing.T) {
+	type config struct {
+		Value string
+	}
+
+	defer func() {
+		parsersMu.Lock()
+		delete(parsers, ".xyz")
+		parsersMu.Unlock()
+	}()
+
+	RegisterParser("xyz", func(r io.Reader, cfg interface{}) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		cfg.(*config).Value = "first:" + string(b)
+		return nil
+	})
+
+	tmpFile, err := os.CreateTemp(os.TempDir(), "*.XYZ")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString("content")
+	assert.NoError(t, err)
+
+	var cfg config
+	err = ReadFromFile(&cfg, tmpFile.Name(), DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, "first:content", cfg.Value)
+
+	RegisterParser(".xyz", func(r io.Reader, cfg interface{}) error {
+		cfg.(*config).Value = "second"
+		return nil
+	})
+
+	cfg = config{}
+	err = ReadFromFile(&cfg, tmpFile.Name(), DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, "second", cfg.Value)
+
+	RegisterParser(".xyz", func(r io.Reader, cfg interface{}) error {
+		return fmt.Errorf("broken")
+	})
+
+	err = ReadFromFile(&cfg, tmpFile.Name(), DefaultFileConfig{})
+	assert.Error(t, err)
+}
+
+func TestReadFromEnvWithFallbacks(t *testing.T) {
+	type Nested struct {
+		Host string `env:"NEW_HOST,OLD_HOST"`
+	}
+
+	type Config struct {
+		Host     string `env:"NEW_HOST,OLD_HOST" env-default:"localhost"`
+		Reversed string `env:"OLD_HOST,NEW_HOST"`
+		Nested   Nested `env-prefix:"APP_"`
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Config
+	}{
+		{
+			name: "none set",
+			env:  map[string]string{},
+			want: Config{Host: "localhost"},
+		},
+		{
+			name: "only fallback set",
+			env: map[string]string{
+				"OLD_HOST":     "old.host",
+				"APP_OLD_HOST": "app.old.host",
+			},
+			want: Config{Host: "old.host", Reversed: "old.host", Nested: Nested{Host: "app.old.host"}},
+		},
+		{
+			name: "first wins",
+			env: map[string]string{
+				"NEW_HOST":     "new.host",
+				"OLD_HOST":     "old.host",
+				"APP_NEW_HOST": "app.new.host",
+				"APP_OLD_HOST": "app.old.host",
+			},
+			want: Config{Host: "new.host", Reversed: "old.host", Nested: Nested{Host: "app.new.host"}},
+		},
+		{
+			name: "set to empty",
+			env: map[string]string{
+				"NEW_HOST": "",
+				"OLD_HOST": "old.host",
+			},
+			want: Config{Host: "", Reversed: "old.host"},
+		},
+		{
+			name: "unprefixed ignored",
+			env: map[string]string{
+				"NEW_HOST": "new.host",
+			},
+			want: Config{Host: "new.host", Reversed: "new.host"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg Config
+			err := ReadFromEnv(&cfg)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestReadFromEnvWithPrefixList(t *testing.T) {
+	type pool struct {
+		Size int `env:"SIZE"`
+	}
+	type database struct {
+		Host  string   `env:"HOST"`
+		Port  int      `env:"PORT,LEGACY_PORT"`
+		User  string   `env:"USER" env-default:"admin"`
+		Hosts []string `env-indexed:"REPLICA"`
+		Pool  pool     `env-prefix:"POOL_"`
+	}
+	type config struct {
+		Database database `env-prefix:"NEW_,OLD_"`
+	}
+
+	os.Setenv("NEW_HOST", "new.local")
+	os.Setenv("OLD_HOST", "old.local")
+	os.Setenv("OLD_LEGACY_PORT", "1000")
+	os.Setenv("OLD_PORT", "2000")
+	os.Setenv("OLD_REPLICA_0", "a")
+	os.Setenv("OLD_REPLICA_1", "b")
+	os.Setenv("OLD_POOL_SIZE", "5")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{Database: database{
+		Host:  "new.local",
+		Port:  2000,
+		User:  "admin",
+		Hosts: []string{"a", "b"},
+		Pool:  pool{Size: 5},
+	}}, cfg)
+
+	os.Setenv("NEW_PORT", "3000")
+	os.Setenv("NEW_REPLICA_0", "c")
+	os.Setenv("NEW_POOL_SIZE", "10")
+
+	cfg = config{}
+	err = ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{Database: database{
+		Host:  "new.local",
+		Port:  3000,
+		User:  "admin",
+		Hosts: []string{"c"},
+		Pool:  pool{Size: 10},
+	}}, cfg)
+}
+
+func TestReadFromEnvWithGlobalPrefix(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT" env-default:"5432"`
+	}
+	type config struct {
+		Name     string   `env:"NAME,OLD_NAME"`
+		Database database `env-prefix:"DB_"`
+	}
+
+	os.Setenv("NAME", "unprefixed")
+	os.Setenv("MYAPP_OLD_NAME", "app")
+	os.Setenv("MYAPP_DB_HOST", "db.local")
+	os.Setenv("DB_PORT", "1000")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnvWithPrefix(&cfg, "MYAPP_")
+	assert.NoError(t, err)
+	assert.Equal(t, config{Name: "app", Database: database{Host: "db.local", Port: 5432}}, cfg)
+
+	cfg = config{}
+	err = ReadFromEnvWithPrefix(&cfg, "")
+	assert.NoError(t, err)
+	assert.Equal(t, config{Name: "unprefixed", Database: database{Port: 1000}}, cfg)
+}
+
+func TestReadFromEnvWithIndexed(t *testing.T) {
+	type config struct {
+		Items []string `env:"ITEMS" env-indexed:"ITEM"`
+		Ports []int    `env-indexed:"PORT" env-default:"80"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "contiguous indices",
+			env:  map[string]string{"ITEM_0": "a", "ITEM_1": "b,c", "ITEM_2": "d", "PORT_0": "8080", "PORT_1": "9090"},
+			want: config{Items: []string{"a", "b,c", "d"}, Ports: []int{8080, 9090}},
+		},
+		{
+			name: "gap stops collection",
+			env:  map[string]string{"ITEM_0": "a", "ITEM_1": "b", "ITEM_3": "d", "PORT_1": "9090"},
+			want: config{Items: []string{"a", "b"}, Ports: []int{80}},
+		},
+		{
+			name: "no indices present",
+			env:  map[string]string{"ITEMS": "x,y"},
+			want: config{Items: []string{"x", "y"}, Ports: []int{80}},
+		},
+		{
+			name: "separated value preferred",
+			env:  map[string]string{"ITEMS": "x", "ITEM_0": "a"},
+			want: config{Items: []string{"x"}, Ports: []int{80}},
+		},
+		{
+			name:    "invalid item",
+			env:     map[string]string{"PORT_0": "http"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := ReadFromEnv(&cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+
+	type invalidType struct {
+		Item string `env-indexed:"ITEM"`
+	}
+	assert.Error(t, ReadFromEnv(&invalidType{}))
+}
+
+type EmbeddedBase struct {
+	Host string `env:"HOST" env-required:"true"`
+	Port int    `env:"PORT" env-default:"80"`
+}
+
+type EmbeddedTLS struct {
+	Cert string `env:"CERT"`
+}
+
+func TestReadFromEnvWithEmbeddedStruct(t *testing.T) {
+	type internal struct {
+		Secret string `env:"SECRET"`
+	}
+	type config struct {
+		EmbeddedBase
+		EmbeddedTLS `env-prefix:"TLS_"`
+		Name        string `env:"NAME"`
+		internal    internal
+	}
+
+	os.Setenv("HOST", "example.com")
+	os.Setenv("TLS_CERT", "cert.pem")
+	os.Setenv("NAME", "app")
+	os.Setenv("SECRET", "ignored")
+	defer os.Clearenv()
+
+	var cfg config
+	sources, err := ReadWithSources(&cfg, nil, "", DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		EmbeddedBase: EmbeddedBase{Host: "example.com", Port: 80},
+		EmbeddedTLS:  EmbeddedTLS{Cert: "cert.pem"},
+		Name:         "app",
+	}, cfg)
+	assert.Equal(t, SourceEnv, sources["Host"])
+	assert.Equal(t, SourceDefault, sources["Port"])
+	assert.Equal(t, SourceEnv, sources["Cert"])
+
+	os.Unsetenv("HOST")
+	err = ReadFromEnv(&config{})
+	assert.EqualError(t, err, `field "Host" is required but the value is not provided`)
+}
+
+func TestReadFromEnvWithEmbeddedUnexportedStruct(t *testing.T) {
+	type base struct {
+		Host   string `env:"HOST" env-required:"true"`
+		Port   int    `env:"PORT" env-default:"80"`
+		hidden string
+	}
+	type config struct {
+		base
+		Name string `env:"NAME"`
+	}
+
+	os.Setenv("HOST", "example.com")
+	os.Setenv("NAME", "app")
+	defer os.Clearenv()
+
+	var cfg config
+	sources, err := ReadWithSources(&cfg, nil, "", DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", cfg.Host)
+	assert.Equal(t, 80, cfg.Port)
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, SourceEnv, sources["Host"])
+	assert.Equal(t, SourceDefault, sources["Port"])
+
+	os.Unsetenv("HOST")
+	err = ReadFromEnv(&config{})
+	assert.EqualError(t, err, `field "Host" is required but the value is not provided`)
+}
+
+func TestReadFromEnvWithNetworkTypes(t *testing.T) {
+	type config struct {
+		IP        net.IP     `env:"TEST_IP"`
+		IPs       []net.IP   `env:"TEST_IPS"`
+		Network   *net.IPNet `env:"TEST_NETWORK"`
+		NetworkV  net.IPNet  `env:"TEST_NETWORK"`
+		Endpoint  url.URL    `env:"TEST_URL"`
+		EndpointP *url.URL   `env:"TEST_URL"`
+	}
+
+	valid := map[string]string{
+		"TEST_IP":      "192.168.1.10",
+		"TEST_IPS":     "10.0.0.1,::1",
+		"TEST_NETWORK": "10.0.0.0/8",
+		"TEST_URL":     "https://user@example.com:8443/path?q=1",
+	}
+	for env, val := range valid {
+		os.Setenv(env, val)
+	}
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.10", cfg.IP.String())
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")}, cfg.IPs)
+	assert.Equal(t, "10.0.0.0/8", cfg.Network.String())
+	assert.True(t, cfg.NetworkV.Contains(net.ParseIP("10.1.2.3")))
+	assert.Equal(t, "example.com:8443", cfg.Endpoint.Host)
+	assert.Equal(t, "https://user@example.com:8443/path?q=1", cfg.EndpointP.String())
+
+	tests := []struct {
+		env     string
+		value   string
+		wantErr string
+	}{
+		{env: "TEST_IP", value: "300.1.1.1", wantErr: `invalid IP address "300.1.1.1"`},
+		{env: "TEST_NETWORK", value: "10.0.0.0/33", wantErr: `invalid CIDR "10.0.0.0/33"`},
+		{env: "TEST_URL", value: "://missing-scheme", wantErr: `invalid URL "://missing-scheme"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			os.Setenv(tt.env, tt.value)
+			defer os.Setenv(tt.env, valid[tt.env])
+
+			err := ReadFromEnv(&config{})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReadFromEnvWithTypedMapKeys(t *testing.T) {
+	type config struct {
+		Names  map[int]string `env:"TEST_NAMES"`
+		Counts map[int]int    `env:"TEST_COUNTS"`
+		Flags  map[uint8]bool `env:"TEST_FLAGS"`
+	}
+
+	os.Setenv("TEST_NAMES", "1:one,-2:minus two")
+	os.Setenv("TEST_COUNTS", "10:100,20:200")
+	os.Setenv("TEST_FLAGS", "7:true")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Names:  map[int]string{1: "one", -2: "minus two"},
+		Counts: map[int]int{10: 100, 20: 200},
+		Flags:  map[uint8]bool{7: true},
+	}, cfg)
+
+	os.Setenv("TEST_COUNTS", "10:100,ten:10")
+	err = ReadFromEnv(&config{})
+	assert.ErrorContains(t, err, `invalid map key "ten"`)
+
+	os.Setenv("TEST_COUNTS", "10:ten")
+	err = ReadFromEnv(&config{})
+	assert.Error(t, err)
+
+	type invalidKey struct {
+		Values map[[2]int]string `env:"TEST_NAMES"`
+	}
+	err = ReadFromEnv(&invalidKey{})
+	assert.EqualError(t, err, "unsupported map key type [2]int")
+}
+
+func TestReadFromEnvWithInlineDefault(t *testing.T) {
+	type config struct {
+		Port     int      `env:"TEST_PORT:5432"`
+		Host     string   `env:"TEST_HOST,TEST_LEGACY_HOST:localhost"`
+		URL      string   `env:"TEST_URL:http://localhost:8080"`
+		Hosts    []string `env:"TEST_HOSTS:a,b"`
+		Name     string   `env:"TEST_NAME:inline" env-default:"tag"`
+		Optional string   `env:"TEST_OPTIONAL"`
+	}
+
+	os.Clearenv()
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Port:  5432,
+		Host:  "localhost",
+		URL:   "http://localhost:8080",
+		Hosts: []string{"a", "b"},
+		Name:  "tag",
+	}, cfg)
+
+	os.Setenv("TEST_PORT", "1234")
+	os.Setenv("TEST_LEGACY_HOST", "legacy")
+	os.Setenv("TEST_OPTIONAL", "set")
+	defer os.Clearenv()
+
+	cfg = config{}
+	err = ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 1234, cfg.Port)
+	assert.Equal(t, "legacy", cfg.Host)
+	assert.Equal(t, "set", cfg.Optional)
+}
+
+func TestReadFromEnvWithPairs(t *testing.T) {
+	type config struct {
+		Headers []Pair `env:"TEST_HEADERS"`
+	}
+
+	os.Setenv("TEST_HEADERS", "z:1,a:2,m:http://host:8080")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []Pair{
+		{Key: "z", Value: "1"},
+		{Key: "a", Value: "2"},
+		{Key: "m", Value: "http://host:8080"},
+	}, cfg.Headers)
+
+	os.Setenv("TEST_HEADERS", "a:1,b")
+	err = ReadFromEnv(&config{})
+	assert.ErrorContains(t, err, `invalid pair item: "b"`)
+}
+
+func TestReadWithEnvMerge(t *testing.T) {
+	type config struct {
+		Replace []string `yaml:"replace" env:"TEST_REPLACE"`
+		Append  []string `yaml:"append" env:"TEST_APPEND" env-merge:"append"`
+		Indexed []int    `yaml:"indexed" env-indexed:"TEST_INDEXED" env-merge:"append"`
+		Unset   []string `yaml:"unset" env:"TEST_UNSET" env-merge:"append"`
+	}
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte("replace: [a, b]\nappend: [a, b]\nindexed: [1]\nunset: [a]\n"), 0600)
+	assert.NoError(t, err)
+
+	os.Setenv("TEST_REPLACE", "c,d")
+	os.Setenv("TEST_APPEND", "c,d")
+	os.Setenv("TEST_INDEXED_0", "2")
+	os.Setenv("TEST_INDEXED_1", "3")
+	defer os.Clearenv()
+
+	var cfg config
+	err = Read(&cfg, nil, file, DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Replace: []string{"c", "d"},
+		Append:  []string{"a", "b", "c", "d"},
+		Indexed: []int{1, 2, 3},
+		Unset:   []string{"a"},
+	}, cfg)
+
+	type invalidMode struct {
+		Values []string `env:"TEST_APPEND" env-merge:"prepend"`
+	}
+	assert.Error(t, ReadFromEnv(&invalidMode{}))
+
+	type invalidType struct {
+		Value string `env:"TEST_APPEND" env-merge:"append"`
+	}
+	assert.Error(t, ReadFromEnv(&invalidType{}))
+}
+
+func TestReadFromEnvWithBase(t *testing.T) {
+	type config struct {
+		Auto     int    `env:"TEST_MODE"`
+		Decimal  int    `env:"TEST_MODE" env-base:"10"`
+		Unsigned uint32 `env:"TEST_MODE" env-base:"10"`
+		Hex      []int  `env:"TEST_HEX" env-base:"16"`
+	}
+
+	os.Setenv("TEST_MODE", "0755")
+	os.Setenv("TEST_HEX", "ff,10")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{Auto: 0755, Decimal: 755, Unsigned: 755, Hex: []int{255, 16}}, cfg)
+
+	type invalidBase struct {
+		Value int `env:"TEST_MODE" env-base:"ten"`
+	}
+	err = ReadFromEnv(&invalidBase{})
+	assert.Error(t, err)
+
+	type mismatch struct {
+		Value int `env:"TEST_MODE" env-base:"2"`
+	}
+	err = ReadFromEnv(&mismatch{})
+	assert.Error(t, err)
+}
+
+func TestReadFromEnvWithTypeHint(t *testing.T) {
+	type config struct {
+		Workers interface{}   `env:"TEST_WORKERS" env-type:"int"`
+		Name    interface{}   `env:"TEST_NAME" env-type:"string"`
+		Ratio   interface{}   `env:"TEST_RATIO" env-type:"float"`
+		Enabled interface{}   `env:"TEST_ENABLED" env-type:"bool"`
+		Timeout interface{}   `env:"TEST_TIMEOUT" env-type:"duration"`
+		Limits  []interface{} `env:"TEST_LIMITS" env-type:"int"`
+		Unset   interface{}   `env:"TEST_UNSET" env-type:"int"`
+	}
+
+	os.Setenv("TEST_WORKERS", "4")
+	os.Setenv("TEST_NAME", "plugin")
+	os.Setenv("TEST_RATIO", "0.5")
+	os.Setenv("TEST_ENABLED", "true")
+	os.Setenv("TEST_TIMEOUT", "5s")
+	os.Setenv("TEST_LIMITS", "1,2")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Workers: 4,
+		Name:    "plugin",
+		Ratio:   0.5,
+		Enabled: true,
+		Timeout: 5 * time.Second,
+		Limits:  []interface{}{1, 2},
+	}, cfg)
+
+	type noHint struct {
+		Value interface{} `env:"TEST_WORKERS"`
+	}
+	err = ReadFromEnv(&noHint{})
+	assert.Error(t, err)
+
+	type invalidHint struct {
+		Value interface{} `env:"TEST_WORKERS" env-type:"complex"`
+	}
+	err = ReadFromEnv(&invalidHint{})
+	assert.Error(t, err)
+
+	type mismatch struct {
+		Value interface{} `env:"TEST_NAME" env-type:"int"`
+	}
+	err = ReadFromEnv(&mismatch{})
+	assert.Error(t, err)
+}
+
+func TestReadFromEnvWithPercent(t *testing.T) {
+	type config struct {
+		CPULimit   float64   `env:"TEST_CPU" env-percent:"true"`
+		Thresholds []float32 `env:"TEST_THRESHOLDS" env-percent:"true"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "percentage",
+			env:  map[string]string{"TEST_CPU": "75%"},
+			want: config{CPULimit: 0.75},
+		},
+		{
+			name: "bare float",
+			env:  map[string]string{"TEST_CPU": "0.75"},
+			want: config{CPULimit: 0.75},
+		},
+		{
+			name: "slice",
+			env:  map[string]string{"TEST_THRESHOLDS": "50%,0.9,100%"},
+			want: config{Thresholds: []float32{0.5, 0.9, 1}},
+		},
+		{
+			name:    "invalid percentage",
+			env:     map[string]string{"TEST_CPU": "abc%"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := ReadFromEnv(&cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.CPULimit, cfg.CPULimit, 1e-9)
+			assert.InDeltaSlice(t, tt.want.Thresholds, cfg.Thresholds, 1e-6)
+		})
+	}
+
+	type invalidType struct {
+		Value int `env:"TEST_CPU" env-percent:"true"`
+	}
+	assert.Error(t, ReadFromEnv(&invalidType{}))
+}
+
+func TestReadFromEnvWithChar(t *testing.T) {
+	type config struct {
+		Separator rune  `env:"TEST_SEPARATOR" env-char:"true"`
+		Delimiter byte  `env:"TEST_DELIMITER" env-char:"true"`
+		Code      int32 `env:"TEST_CODE"`
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected config
+		err      string
+	}{
+		{
+			name:     "single char",
+			env:      map[string]string{"TEST_SEPARATOR": ",", "TEST_DELIMITER": ";"},
+			expected: config{Separator: ',', Delimiter: ';'},
+		},
+		{
+			name:     "multi-byte rune",
+			env:      map[string]string{"TEST_SEPARATOR": "§"},
+			expected: config{Separator: '§'},
+		},
+		{
+			name: "multiple chars",
+			env:  map[string]string{"TEST_SEPARATOR": ",;"},
+			err:  `invalid character ",;", exactly one character is required`,
+		},
+		{
+			name: "empty",
+			env:  map[string]string{"TEST_SEPARATOR": ""},
+			err:  `invalid character "", exactly one character is required`,
+		},
+		{
+			name: "multi-byte byte",
+			env:  map[string]string{"TEST_DELIMITER": "§"},
+			err:  `invalid byte "§", exactly one single-byte character is required`,
+		},
+		{
+			name:     "numeric without tag",
+			env:      map[string]string{"TEST_CODE": "44"},
+			expected: config{Code: 44},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Clearenv()
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := ReadFromEnv(&cfg)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+
+	type invalid struct {
+		Value int `env:"TEST_VALUE" env-char:"true"`
+	}
+	err := ReadFromEnv(&invalid{})
+	assert.EqualError(t, err, `env-char on field "Value" requires a rune or byte field, got int`)
+}
+
+func TestReadFromEnvWithExtendedBool(t *testing.T) {
+	type config struct {
+		Enabled bool `env:"TEST_ENABLED"`
+	}
+
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{value: "yes", want: true},
+		{value: "YES", want: true},
+		{value: "no", want: false},
+		{value: "On", want: true},
+		{value: "off", want: false},
+		{value: "enabled", want: true},
+		{value: "Disabled", want: false},
+		{value: "true", want: true},
+		{value: "0", want: false},
+		{value: "T", want: true},
+		{value: " true ", want: true},
+		{value: " yes\n", want: true},
+		{value: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			os.Setenv("TEST_ENABLED", tt.value)
+			defer os.Clearenv()
+
+			cfg := config{Enabled: !tt.want}
+			err := ReadFromEnv(&cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Enabled)
+		})
+	}
+}
+
+func TestReadFromEnvWithSize(t *testing.T) {
+	type config struct {
+		MaxUpload int64    `env:"TEST_SIZE" env-size:"true"`
+		Buffer    uint32   `env:"TEST_BUFFER" env-size:"true"`
+		Limits    []uint64 `env:"TEST_LIMITS" env-size:"true"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "decimal unit",
+			env:  map[string]string{"TEST_SIZE": "10MB"},
+			want: config{MaxUpload: 10 * 1000 * 1000},
+		},
+		{
+			name: "binary unit",
+			env:  map[string]string{"TEST_SIZE": "1GiB", "TEST_BUFFER": "512KiB"},
+			want: config{MaxUpload: 1 << 30, Buffer: 512 * 1024},
+		},
+		{
+			name: "bare integer and slice",
+			env:  map[string]string{"TEST_SIZE": "4096", "TEST_LIMITS": "1kb,2 MiB"},
+			want: config{MaxUpload: 4096, Limits: []uint64{1000, 2 << 20}},
+		},
+		{
+			name:    "invalid unit",
+			env:     map[string]string{"TEST_SIZE": "10XB"},
+			wantErr: true,
+		},
+		{
+			name:    "negative size",
+			env:     map[string]string{"TEST_SIZE": "-1MB"},
+			wantErr: true,
+		},
+		{
+			name:    "overflow",
+			env:     map[string]string{"TEST_BUFFER": "8GiB"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := ReadFromEnv(&cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+
+	type invalidType struct {
+		Value string `env:"TEST_SIZE" env-size:"true"`
+	}
+	assert.Error(t, ReadFromEnv(&invalidType{}))
+}
+
+type upperField string
+
+func (f *upperField) SetValue(s string) error {
+	if s == "" {
+		return fmt.Errorf("value can't be empty")
+	}
+	*f = upperField(strings.ToUpper(s))
+	return nil
+}
+
+func TestReadFromEnvWithElementSetter(t *testing.T) {
+	type config struct {
+		Single   upperField            `env:"TEST_SINGLE"`
+		Slice    []upperField          `env:"TEST_SLICE"`
+		Pointers []*upperField         `env:"TEST_POINTERS"`
+		Map      map[string]upperField `env:"TEST_MAP"`
+		Keys     map[upperField]int    `env:"TEST_KEYS"`
+	}
+
+	os.Setenv("TEST_SINGLE", "a")
+	os.Setenv("TEST_SLICE", "a,b")
+	os.Setenv("TEST_POINTERS", "c")
+	os.Setenv("TEST_MAP", "one:x,two:y")
+	os.Setenv("TEST_KEYS", "k:1")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+
+	pointer := upperField("C")
+	assert.Equal(t, config{
+		Single:   "A",
+		Slice:    []upperField{"A", "B"},
+		Pointers: []*upperField{&pointer},
+		Map:      map[string]upperField{"one": "X", "two": "Y"},
+		Keys:     map[upperField]int{"K": 1},
+	}, cfg)
+
+	os.Setenv("TEST_SLICE", "a,,b")
+	err = ReadFromEnv(&config{})
+	assert.EqualError(t, err, "value can't be empty")
+
+	os.Setenv("TEST_SLICE", "a")
+	os.Setenv("TEST_MAP", "one:")
+	err = ReadFromEnv(&config{})
+	assert.EqualError(t, err, "value can't be empty")
+}
+
+func TestParseINI(t *testing.T) {
+	type server struct {
+		Host  string   `yaml:"host"`
+		Port  int      `json:"port"`
+		Tags  []string `yaml:"tags"`
+		Roles []string `yaml:"roles" env-separator:","`
+	}
+	type config struct {
+		Name   string
+		Server server `yaml:"server"`
+		Backup server `env-separator:";"`
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "single section",
+			file: `
+[server]
+host = example.com
+port = 8080`,
+			want: config{Server: server{Host: "example.com", Port: 8080}},
+		},
+		{
+			name: "multiple sections",
+			file: `
+name = app
+
+[SERVER]
+HOST = example.com
+port = 8080
+
+# comment
+[backup]
+host = backup.example.com
+port = 9090`,
+			want: config{Name: "app", Server: server{Host: "example.com", Port: 8080}, Backup: server{Host: "backup.example.com", Port: 9090}},
+		},
+		{
+			name: "unknown keys and sections",
+			file: `
+unknown = value
+
+[server]
+host = example.com
+unknown = value
+
+[unknown]
+host = other.com`,
+			want: config{Server: server{Host: "example.com"}},
+		},
+		{
+			name: "key of nested structure",
+			file: `
+server = example.com
+
+[server]
+host = example.com`,
+			want: config{Server: server{Host: "example.com"}},
+		},
+		{
+			name: "inherited separator",
+			file: `
+[server]
+tags = a,b
+
+[backup]
+tags = a,b;c
+roles = x,y`,
+			want: config{Server: server{Tags: []string{"a", "b"}}, Backup: server{Tags: []string{"a,b", "c"}, Roles: []string{"x", "y"}}},
+		},
+		{
+			name:    "invalid section",
+			file:    "[server",
+			wantErr: true,
+		},
+		{
+			name:    "invalid pair",
+			file:    "name",
+			wantErr: true,
+		},
+		{
+			name: "invalid value",
+			file: `
+[server]
+port = abc`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config
+			err := parseINI(strings.NewReader(tt.file), &cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	type pool struct {
+		Size  int      `yaml:"size"`
+		Hosts []string `yaml:"hosts"`
+	}
+	type database struct {
+		Host string   `yaml:"host"`
+		Port int      `yaml:"port"`
+		Tags []string `yaml:"tags"`
+		Pool pool     `yaml:"pool" env-separator:";"`
+	}
+	type config struct {
+		Name     string   `yaml:"name"`
+		Database database `yaml:"database"`
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "dotted keys",
+			file: `
+# comment
+! another comment
+name = app
+database.host = db.local
+database.port: 5432
+database.pool.size=10`,
+			want: config{Name: "app", Database: database{Host: "db.local", Port: 5432, Pool: pool{Size: 10}}},
+		},
+		{
+			name: "continued line",
+			file: `
+database.tags = a,\
+                b,\
+                c`,
+			want: config{Database: database{Tags: []string{"a", "b", "c"}}},
+		},
+		{
+			name: "inherited separator",
+			file: "database.pool.hosts = a,b;c",
+			want: config{Database: database{Pool: pool{Hosts: []string{"a,b", "c"}}}},
+		},
+		{
+			name: "unknown keys",
+			file: `
+unknown = value
+database.unknown = value
+name.sub = value
+database = value`,
+			want: config{},
+		},
+		{
+			name: "escaped backslash at line end",
+			file: `
+name = C:\\
+database.host = x`,
+			want: config{Name: `C:\`, Database: database{Host: "x"}},
+		},
+		{
+			name: "escape sequences",
+			file: `
+name = a\=b\:c\u00e9\n
+database.ho\st = x\ 
+database.port 5432`,
+			want: config{Name: "a=b:c\u00e9\n", Database: database{Host: "x ", Port: 5432}},
+		},
+		{
+			name:    "invalid unicode escape",
+			file:    `name = \u00zz`,
+			wantErr: true,
+		},
+		{
+			name: "key without value",
+			file: "name = app\nname\ndatabase.tags",
+			want: config{Database: database{Tags: []string{}}},
+		},
+		{
+			name:    "invalid value",
+			file:    "database.port = abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config
+			err := parseProperties(strings.NewReader(tt.file), &cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestReadFromEnvWithPrefixSeparator(t *testing.T) {
+	type Pool struct {
+		Size int `env:"SIZE"`
+	}
+
+	type DBConfig struct {
+		Host string `env:"HOST"`
+		Pool Pool   `env-prefix:"POOL"`
+		Raw  Pool   `env-prefix:"RAW" env-prefix-separator:""`
+	}
+
+	type Config struct {
+		Default  DBConfig `env-prefix:"DB" env-prefix-separator:"_"`
+		ReadOnly DBConfig `env-prefix:"READONLY_" env-prefix-separator:"_"`
+		Dotted   DBConfig `env-prefix:"DOTTED" env-prefix-separator:"."`
+		Legacy   DBConfig `env-prefix:"LEGACY"`
+	}
+
+	var env = map[string]string{
+		"DB_HOST":            "db1.host",
+		"DB_POOL_SIZE":       "1",
+		"DB_RAWSIZE":         "10",
+		"READONLY_HOST":      "db2.host",
+		"READONLY_POOL_SIZE": "2",
+		"READONLY_RAWSIZE":   "20",
+		"DOTTED.HOST":        "db3.host",
+		"DOTTED.POOL.SIZE":   "3",
+		"DOTTED.RAWSIZE":     "30",
+		"LEGACYHOST":         "db4.host",
+		"LEGACYPOOLSIZE":     "4",
+		"LEGACYRAWSIZE":      "40",
+		"LEGACY_POOL_SIZE":   "-1",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	defer os.Clearenv()
+
+	var cfg Config
+	if err := ReadFromEnv(&cfg); err != nil {
+		t.Fatal("failed to read env vars", err)
+	}
+
+	var expected = Config{
+		Default:  DBConfig{Host: "db1.host", Pool: Pool{Size: 1}, Raw: Pool{Size: 10}},
+		ReadOnly: DBConfig{Host: "db2.host", Pool: Pool{Size: 2}, Raw: Pool{Size: 20}},
+		Dotted:   DBConfig{Host: "db3.host", Pool: Pool{Size: 3}, Raw: Pool{Size: 30}},
+		Legacy:   DBConfig{Host: "db4.host", Pool: Pool{Size: 4}, Raw: Pool{Size: 40}},
+	}
+
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("wrong data %v, want %v", cfg, expected)
+	}
+}
+
+func TestReadWithEnvPrefixSeparatorOption(t *testing.T) {
+	type Logging struct {
+		Debug bool `env:"DEBUG"`
+	}
+
+	type DBConfig struct {
+		Host    string  `env:"HOST"`
+		Logging Logging `env-prefix:"LOG"`
+		Legacy  Logging `env-prefix:"LEGACY" env-prefix-separator:"_"`
+	}
+
+	type Config struct {
+		DB DBConfig `env-prefix:"DB"`
+	}
+
+	var env = map[string]string{
+		"DB.HOST":          "db.host",
+		"DB.LOG.DEBUG":     "true",
+		"DB.LEGACY_DEBUG":  "true",
+		"DBHOST":           "ignored",
+		"DB_LOG_DEBUG":     "false",
+		"DB.LEGACY.DEBUG":  "false",
+		"DBLOGDEBUG":       "false",
+		"DB.LEGACYDEBUG":   "false",
+		"DB.LEGACY__DEBUG": "false",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	defer os.Clearenv()
+
+	var cfg Config
+	err := ReadWithOptions(&cfg, nil, "", DefaultFileConfig{}, ReadOptions{EnvPrefixSeparator: "."})
+	assert.NoError(t, err)
+	assert.Equal(t, Config{DB: DBConfig{Host: "db.host", Logging: Logging{Debug: true}, Legacy: Logging{Debug: true}}}, cfg)
+
+	cfg = Config{}
+	err = ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, Config{DB: DBConfig{Host: "ignored"}}, cfg)
+}
+
+func TestReadFromEnvWithCSV(t *testing.T) {
+	type config struct {
+		Plain  []string `env:"TEST_LIST"`
+		CSV    []string `env:"TEST_LIST" env-csv:"true"`
+		Quotes []string `env:"TEST_QUOTES" env-csv:"true"`
+		Semi   []string `env:"TEST_SEMI" env-csv:"true" env-separator:";"`
+		Ints   []int    `env:"TEST_INTS" env-csv:"true"`
+	}
+
+	os.Setenv("TEST_LIST", `a,"b,c",d`)
+	os.Setenv("TEST_QUOTES", `"say ""hi""",x`)
+	os.Setenv("TEST_SEMI", `a;"b;c"`)
+	os.Setenv("TEST_INTS", `1,"2",3`)
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Plain:  []string{"a", `"b`, `c"`, "d"},
+		CSV:    []string{"a", "b,c", "d"},
+		Quotes: []string{`say "hi"`, "x"},
+		Semi:   []string{"a", "b;c"},
+		Ints:   []int{1, 2, 3},
+	}, cfg)
+
+	os.Setenv("TEST_LIST", `a,"b`)
+	err = ReadFromEnv(&config{})
+	assert.Error(t, err)
+
+	type invalidSeparator struct {
+		List []string `env:"TEST_LIST" env-csv:"true" env-separator:"::"`
+	}
+	err = ReadFromEnv(&invalidSeparator{})
+	assert.Error(t, err)
+}
+
+func TestReadFromEnvWithEscapedSeparator(t *testing.T) {
+	type config struct {
+		List     []string `env:"TEST_LIST"`
+		Semi     []string `env:"TEST_SEMI" env-separator:";"`
+		Multi    []string `env:"TEST_MULTI" env-separator:"::"`
+		Trailing []string `env:"TEST_TRAILING"`
+		Ints     []int    `env:"TEST_INTS" env-separator:";"`
+	}
+
+	os.Setenv("TEST_LIST", `a,b\,c,d`)
+	os.Setenv("TEST_SEMI", `a\;b;c,d;\e`)
+	os.Setenv("TEST_MULTI", `a::b\::c`)
+	os.Setenv("TEST_TRAILING", `a,b\`)
+	os.Setenv("TEST_INTS", `1;2`)
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		List:     []string{"a", "b,c", "d"},
+		Semi:     []string{"a;b", "c,d", `\e`},
+		Multi:    []string{"a", "b::c"},
+		Trailing: []string{"a", `b\`},
+		Ints:     []int{1, 2},
+	}, cfg)
+
+	os.Setenv("TEST_LIST", `a\,`)
+	cfg = config{}
+	err = ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a,"}, cfg.List)
+}
+
+func TestReadFromEnvWithRequiredGroup(t *testing.T) {
+	type Auth struct {
+		Token    string `env:"TEST_TOKEN" env-required-group:"auth"`
+		Password string `env:"TEST_PASSWORD" env-required-group:"auth"`
+	}
+
+	type config struct {
+		Auth Auth
+		Host string `env:"TEST_HOST" env-required-group:"target"`
+		IP   string `env:"TEST_IP" env-required-group:"target"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name: "one set",
+			env:  map[string]string{"TEST_TOKEN": "token", "TEST_IP": "127.0.0.1"},
+		},
+		{
+			name: "multiple set",
+			env:  map[string]string{"TEST_TOKEN": "token", "TEST_PASSWORD": "password", "TEST_HOST": "localhost", "TEST_IP": "127.0.0.1"},
+		},
+		{
+			name:    "none set",
+			env:     map[string]string{},
+			wantErr: `at least one field of group "target" (Host, IP) is required but no value is provided`,
+		},
+		{
+			name:    "nested group unset",
+			env:     map[string]string{"TEST_HOST": "localhost"},
+			wantErr: `at least one field of group "auth" (Token, Password) is required but no value is provided`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			err := ReadFromEnv(&config{})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadFromEnvWithPresentTrue(t *testing.T) {
+	type config struct {
+		Feature    bool `env:"FEATURE_X" env-present-true:"true"`
+		Default    bool `env:"FEATURE_Y" env-present-true:"true" env-default:"true"`
+		NotPresent bool `env:"FEATURE_Z" env-present-true:"false"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "absent",
+			env:  map[string]string{},
+			want: config{Default: true},
+		},
+		{
+			name: "present empty",
+			env:  map[string]string{"FEATURE_X": ""},
+			want: config{Feature: true, Default: true},
+		},
+		{
+			name: "present false",
+			env:  map[string]string{"FEATURE_X": "false", "FEATURE_Y": "false"},
+			want: config{Feature: true, Default: true},
+		},
+		{
+			name: "regular bool",
+			env:  map[string]string{"FEATURE_Z": "true"},
+			want: config{NotPresent: true, Default: true},
+		},
+		{
+			name:    "regular bool empty",
+			env:     map[string]string{"FEATURE_Z": ""},
+			want:    config{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := ReadFromEnv(&cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+
+	type invalid struct {
+		Feature string `env:"FEATURE_X" env-present-true:"true"`
+	}
+	assert.Error(t, ReadFromEnv(&invalid{}))
+}
+
+func TestParseHCL(t *testing.T) {
+	type server struct {
+		Host string   `hcl:"host"`
+		Port int      `yaml:"port"`
+		Tags []string `hcl:"tags"`
+	}
+	type config struct {
+		Name    string            `hcl:"name"`
+		Labels  map[string]string `yaml:"labels"`
+		Server  server            `hcl:"server,block"`
+		Backend server            `yaml:"backend"`
+		Servers map[string]server `hcl:"servers"`
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "flat",
+			file: `
+name   = "app"
+labels = { env = "prod" }
+other  = "ignored"`,
+			want: config{Name: "app", Labels: map[string]string{"env": "prod"}},
+		},
+		{
+			name: "nested blocks",
+			file: `
+name = "app"
+
+server {
+  host = "example.com"
+  port = 8080
+  tags = ["a", "b"]
+}
+
+backend "primary" {
+  host = "ignored"
+}`,
+			want: config{Name: "app", Server: server{Host: "example.com", Port: 8080, Tags: []string{"a", "b"}}},
+		},
+		{
+			name: "labeled blocks of the same type",
+			file: `
+servers "a" {
+  host = "a.example.com"
+}
+
+servers "b" {
+  port = 8080
+}`,
+			want: config{Servers: map[string]server{"a": {Host: "a.example.com"}, "b": {Port: 8080}}},
+		},
+		{
+			name:    "duplicate block",
+			file:    "server {\n  port = 1\n}\nserver {\n  port = 2\n}",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate labeled block",
+			file:    "servers \"a\" {}\nservers \"a\" {}",
+			wantErr: true,
+		},
+		{
+			name:    "syntax error",
+			file:    "name = ",
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			file:    "server {\n  port = \"abc\"\n}",
+			wantErr: true,
+		},
+		{
+			name:    "variables",
+			file:    "name = var.name",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config
+			err := parseHCL(strings.NewReader(tt.file), &cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestReadWithOptionsSkipEmptyEnv(t *testing.T) {
+	type config struct {
+		Host string `env:"TEST_HOST" env-default:"localhost"`
+		Name string `env:"TEST_NAME,TEST_NAME_OLD"`
+	}
+
+	os.Setenv("TEST_HOST", "")
+	os.Setenv("TEST_NAME", "")
+	os.Setenv("TEST_NAME_OLD", "old")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadWithOptions(&cfg, nil, "", DefaultFileConfig{}, ReadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "", Name: ""}, cfg)
+
+	cfg = config{}
+	err = ReadWithOptions(&cfg, nil, "", DefaultFileConfig{}, ReadOptions{SkipEmptyEnv: true})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "localhost", Name: "old"}, cfg)
+
+	os.Setenv("TEST_HOST", "example.com")
+	cfg = config{}
+	err = ReadWithOptions(&cfg, nil, "", DefaultFileConfig{}, ReadOptions{SkipEmptyEnv: true})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "example.com", Name: "old"}, cfg)
+}
+
+func TestReadFromFlagsAndFile(t *testing.T) {
+	type config struct {
+		Host string `yaml:"host" env:"TEST_HOST"`
+		Port int    `flag:"port" env:"TEST_PORT"`
+		Name string `env:"TEST_NAME" env-default:"default"`
+	}
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("host: file.local"), 0600))
+
+	os.Setenv("TEST_HOST", "env.local")
+	os.Setenv("TEST_PORT", "1000")
+	os.Setenv("TEST_NAME", "env")
+	defer os.Clearenv()
+
+	flagSet := &pflag.FlagSet{}
+	flagSet.Int("port", 2000, "")
+
+	var cfg config
+	err := ReadFromFlagsAndFile(&cfg, flagSet, file, DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "file.local", Port: 2000, Name: "default"}, cfg)
+
+	cfg = config{}
+	err = ReadWithOptions(&cfg, nil, file, DefaultFileConfig{}, ReadOptions{SkipEnv: true, ReadEnvFiles: true})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "file.local", Name: "default"}, cfg)
+
+	cfg = config{}
+	err = Read(&cfg, flagSet, file, DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "env.local", Port: 1000, Name: "env"}, cfg)
+}
+
+type fakeSecretResolver map[string]string
+
+func (r fakeSecretResolver) Resolve(ref string) (string, error) {
+	value, ok := r[ref]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", ref)
+	}
+	return value, nil
+}
+
+func TestReadWithSecretResolver(t *testing.T) {
+	type config struct {
+		User     string `env:"TEST_USER" env-secret-ref:"true"`
+		Password string `env:"TEST_PASSWORD" env-secret-ref:"true" env-default:"default"`
+		Port     int    `env:"TEST_PORT" env-secret-ref:"true"`
+		Host     string `env:"TEST_HOST"`
+	}
+
+	resolver := fakeSecretResolver{"db#user": "admin", "db#port": "5432"}
+	opts := ReadOptions{SecretResolver: resolver}
+
+	os.Setenv("TEST_USER", "db#user")
+	os.Setenv("TEST_PORT", "db#port")
+	os.Setenv("TEST_HOST", "db#host")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadWithOptions(&cfg, nil, "", DefaultFileConfig{}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, config{User: "admin", Password: "default", Port: 5432, Host: "db#host"}, cfg)
+
+	os.Setenv("TEST_PASSWORD", "db#password")
+	err = ReadWithOptions(&config{}, nil, "", DefaultFileConfig{}, opts)
+	assert.EqualError(t, err, `resolving secret reference of TEST_PASSWORD: secret "db#password" not found`)
+
+	err = ReadWithOptions(&config{}, nil, "", DefaultFileConfig{}, ReadOptions{})
+	assert.ErrorContains(t, err, "holds a secret reference, but no SecretResolver is configured")
+}
+
+func TestReadEnvFiles(t *testing.T) {
+	type config struct {
+		Password string `env:"DB_PASSWORD" env-default:"default"`
+	}
+
+	secret := filepath.Join(t.TempDir(), "db")
+	err := os.WriteFile(secret, []byte("  s3cret\n"), 0600)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		opts    ReadOptions
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "file present",
+			env:  map[string]string{"DB_PASSWORD_FILE": secret},
+			opts: ReadOptions{ReadEnvFiles: true},
+			want: config{Password: "s3cret"},
+		},
+		{
+			name: "env preferred over file",
+			env:  map[string]string{"DB_PASSWORD": "plain", "DB_PASSWORD_FILE": secret},
+			opts: ReadOptions{ReadEnvFiles: true},
+			want: config{Password: "plain"},
+		},
+		{
+			name: "file absent",
+			opts: ReadOptions{ReadEnvFiles: true},
+			want: config{Password: "default"},
+		},
+		{
+			name: "option disabled",
+			env:  map[string]string{"DB_PASSWORD_FILE": secret},
+			want: config{Password: "default"},
+		},
+		{
+			name:    "missing file",
+			env:     map[string]string{"DB_PASSWORD_FILE": filepath.Join(t.TempDir(), "missing")},
+			opts:    ReadOptions{ReadEnvFiles: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := ReadWithOptions(&cfg, nil, "", DefaultFileConfig{}, tt.opts)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "DB_PASSWORD_FILE")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestReadFromXMLFile(t *testing.T) {
+	type server struct {
+		Host string `xml:"host"`
+		Port int    `xml:"port,attr"`
+	}
+	type config struct {
+		Name    string   `xml:"name"`
+		Debug   bool     `xml:"debug"`
+		Tags    []string `xml:"tags>tag"`
+		Server  server   `xml:"server"`
+		Timeout int      `xml:"timeout" env:"TEST_TIMEOUT" env-default:"30"`
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		want    *config
+		wantErr bool
+	}{
+		{
+			name: "flat",
+			file: `<config><name>app</name><debug>true</debug><unknown>x</unknown></config>`,
+			want: &config{Name: "app", Debug: true, Timeout: 30},
+		},
+		{
+			name: "nested",
+			file: `<?xml version="1.0"?>
+<config>
+  <name>app</name>
+  <tags><tag>a</tag><tag>b</tag></tags>
+  <server port="8080"><host>example.com</host></server>
+  <timeout>10</timeout>
+</config>`,
+			want: &config{Name: "app", Tags: []string{"a", "b"}, Server: server{Host: "example.com", Port: 8080}, Timeout: 10},
+		},
+		{
+			name:    "malformed",
+			file:    `<config><name>app</config>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp(os.TempDir(), "*.xml")
+			if err != nil {
+				t.Fatal("cannot create temporary file:", err)
+			}
+			defer os.Remove(tmpFile.Name())
+
+			if _, err = tmpFile.WriteString(tt.file); err != nil {
+				t.Fatal("failed to write to temporary file:", err)
+			}
+
+			var cfg config
+			if err = ReadFromFile(&cfg, tmpFile.Name(), DefaultFileConfig{}); (err != nil) != tt.wantErr {
+				t.Errorf("wrong error behavior %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(&cfg, tt.want) {
+				t.Errorf("wrong data %v, want %v", &cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadWithDefaulters(t *testing.T) {
+	type database struct {
+		Host string `env:"TEST_DB_HOST"`
+	}
+	type config struct {
+		Workers  int    `env:"TEST_WORKERS"`
+		Hostname string `env:"TEST_HOSTNAME" env-required:"true"`
+		Static   string `env:"TEST_STATIC" env-default:"static"`
+		Database database
+	}
+
+	defaulters := map[string]func() string{
+		"Workers":       func() string { return strconv.Itoa(2 * 4) },
+		"Hostname":      func() string { return "computed.host" },
+		"Static":        func() string { return "computed" },
+		"Database.Host": func() string { return "db.host" },
+	}
+
+	os.Setenv("TEST_HOSTNAME", "explicit.host")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadWithDefaulters(&cfg, defaulters, nil, "", DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Workers: 8, Hostname: "explicit.host", Static: "static", Database: database{Host: "db.host"}}, cfg)
+
+	os.Unsetenv("TEST_HOSTNAME")
+	cfg = config{}
+	err = ReadWithDefaulters(&cfg, defaulters, nil, "", DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, "computed.host", cfg.Hostname)
+
+	err = ReadWithDefaulters(&config{}, map[string]func() string{"Workers": func() string { return "many" }}, nil, "", DefaultFileConfig{})
+	assert.Error(t, err)
+
+	err = ReadWithDefaulters(&config{}, map[string]func() string{"Unknown": func() string { return "" }}, nil, "", DefaultFileConfig{})
+	assert.Error(t, err)
+}
+
+func TestReadFromEnvWithDurations(t *testing.T) {
+	type config struct {
+		Timeout  time.Duration            `env:"TEST_TIMEOUT"`
+		Retries  []time.Duration          `env:"TEST_RETRIES"`
+		Timeouts map[string]time.Duration `env:"TEST_TIMEOUTS"`
+	}
+
+	os.Setenv("TEST_TIMEOUT", "1m30s")
+	os.Setenv("TEST_RETRIES", "1s,2s,3s")
+	os.Setenv("TEST_TIMEOUTS", "a:1s,b:2s")
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Timeout:  90 * time.Second,
+		Retries:  []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+		Timeouts: map[string]time.Duration{"a": time.Second, "b": 2 * time.Second},
+	}, cfg)
+
+	os.Setenv("TEST_RETRIES", "1s,2x")
+	err = ReadFromEnv(&config{})
+	assert.Error(t, err)
+
+	os.Setenv("TEST_RETRIES", "1s")
+	os.Setenv("TEST_TIMEOUTS", "a:1x")
+	err = ReadFromEnv(&config{})
+	assert.Error(t, err)
+
+	os.Setenv("TEST_TIMEOUTS", "a:1s,b:500ms,c:100,d:-5")
+	cfg = config{}
+	err = ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"a": time.Second,
+		"b": 500 * time.Millisecond,
+		"c": 100 * time.Nanosecond,
+		"d": -5 * time.Nanosecond,
+	}, cfg.Timeouts)
+
+	os.Setenv("TEST_TIMEOUT", "100")
+	err = ReadFromEnv(&config{})
+	assert.Error(t, err)
+}
+
+func TestReadWithOptionsMergeYAMLDocuments(t *testing.T) {
+	type config struct {
+		Host   string            `yaml:"host"`
+		Port   int               `yaml:"port"`
+		Labels map[string]string `yaml:"labels"`
+	}
+
+	tmpFile, err := os.CreateTemp(os.TempDir(), "*.yaml")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString(`
+host: example.com
+port: 8080
+labels:
+  a: "1"
+---
+port: 9090
+labels:
+  b: "2"
+`)
+	assert.NoError(t, err)
+
+	var cfg config
+	err = ReadWithOptions(&cfg, nil, tmpFile.Name(), DefaultFileConfig{}, ReadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "example.com", Port: 8080, Labels: map[string]string{"a": "1"}}, cfg)
+
+	cfg = config{}
+	err = ReadWithOptions(&cfg, nil, tmpFile.Name(), DefaultFileConfig{}, ReadOptions{MergeYAMLDocuments: true})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "example.com", Port: 9090, Labels: map[string]string{"a": "1", "b": "2"}}, cfg)
+
+	err = os.WriteFile(tmpFile.Name(), []byte("port: 1\n---\nport: abc\n"), 0600)
+	assert.NoError(t, err)
+
+	err = ReadWithOptions(&config{}, nil, tmpFile.Name(), DefaultFileConfig{}, ReadOptions{MergeYAMLDocuments: true})
+	assert.Error(t, err)
+
+	defer RegisterParser(".yaml", parseYAML)
+	RegisterParser(".yaml", func(r io.Reader, cfg interface{}) error {
+		cfg.(*config).Host = "override"
+		return nil
+	})
+
+	cfg = config{}
+	err = ReadWithOptions(&cfg, nil, tmpFile.Name(), DefaultFileConfig{}, ReadOptions{MergeYAMLDocuments: true})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "override"}, cfg)
+}
+
+func TestReadWithOptionsDeriveFlagNames(t *testing.T) {
+	type config struct {
+		ListenPort int    `yaml:"listen_port"`
+		LogFormat  string `json:"log-format,omitempty"`
+		MaxRetries int
+		Explicit   string `yaml:"explicit" flag:"other"`
+		Ignored    string `yaml:"-"`
+	}
+
+	flagSet := &pflag.FlagSet{}
+	flagSet.Int("listen_port", 0, "")
+	flagSet.String("log-format", "text", "")
+	flagSet.Int("max-retries", 0, "")
+	flagSet.String("explicit", "", "")
+	flagSet.String("other", "", "")
+	flagSet.String("ignored", "", "")
+	assert.NoError(t, flagSet.Parse([]string{"--listen_port", "8080", "--max-retries", "3", "--explicit", "x", "--other", "y", "--ignored", "z"}))
+
+	var cfg config
+	err := ReadWithOptions(&cfg, flagSet, "", DefaultFileConfig{}, ReadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Explicit: "y"}, cfg)
+
+	cfg = config{}
+	err = ReadWithOptions(&cfg, flagSet, "", DefaultFileConfig{}, ReadOptions{DeriveFlagNames: true})
+	assert.NoError(t, err)
+	assert.Equal(t, config{ListenPort: 8080, LogFormat: "text", MaxRetries: 3, Explicit: "y", Ignored: "z"}, cfg)
+}
+
+func TestReadWithOptionsDeriveNestedFlagNames(t *testing.T) {
+	type DB struct {
+		Host string
+		Port int `yaml:"port"`
+	}
+
+	type Common struct {
+		Level string
+	}
+
+	type config struct {
+		Common
+		Host     string
+		DB       DB
+		Database DB `yaml:"database"`
+		Timeout  time.Duration
+	}
+
+	flagSet := &pflag.FlagSet{}
+	flagSet.String("host", "", "")
+	flagSet.String("db-host", "", "")
+	flagSet.Int("db-port", 0, "")
+	flagSet.String("database", "", "")
+	flagSet.String("database-host", "", "")
+	flagSet.String("level", "", "")
+	flagSet.Duration("timeout", 0, "")
+	assert.NoError(t, flagSet.Parse([]string{"--host", "a", "--db-host", "b", "--db-port", "1", "--database", "x",
+		"--database-host", "c", "--level", "debug", "--timeout", "1s"}))
+
+	var cfg config
+	err := ReadWithOptions(&cfg, flagSet, "", DefaultFileConfig{}, ReadOptions{DeriveFlagNames: true})
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Common:   Common{Level: "debug"},
+		Host:     "a",
+		DB:       DB{Host: "b", Port: 1},
+		Database: DB{Host: "c"},
+		Timeout:  time.Second,
+	}, cfg)
+}
+
+func TestMustRead(t *testing.T) {
+	type config struct {
+		Host string `env:"TEST_HOST" env-default:"localhost"`
+		Port int    `env:"TEST_PORT" env-required:"true"`
+	}
+
+	os.Setenv("TEST_PORT", "8080")
+	defer os.Clearenv()
+
+	var cfg config
+	assert.NotPanics(t, func() { MustRead(&cfg, nil, "", DefaultFileConfig{}) })
+	assert.Equal(t, config{Host: "localhost", Port: 8080}, cfg)
+
+	os.Unsetenv("TEST_PORT")
+	assert.PanicsWithError(t, `An error occurred while reading the config into *libstandard.config! field "Port" is required but the value is not provided`, func() {
+		MustRead(&config{}, nil, "", DefaultFileConfig{})
+	})
+}
+
+func TestReadFromEnvWithSliceAndMapDefaults(t *testing.T) {
+	type config struct {
+		Tags      []string       `env:"TEST_TAGS" env-default:"a,b,c"`
+		Ports     []int          `env:"TEST_PORTS" env-default:"1|2" env-separator:"|"`
+		Labels    map[string]int `env:"TEST_LABELS" env-default:"a:1,b:2"`
+		Weights   map[string]int `env:"TEST_WEIGHTS" env-default:"a:1;b:2" env-separator:";"`
+		Preserved []string       `env:"TEST_PRESERVED" env-default:"x"`
+	}
+
+	expected := config{
+		Tags:      []string{"a", "b", "c"},
+		Ports:     []int{1, 2},
+		Labels:    map[string]int{"a": 1, "b": 2},
+		Weights:   map[string]int{"a": 1, "b": 2},
+		Preserved: []string{"kept"},
+	}
+
+	os.Clearenv()
+	cfg := config{Preserved: []string{"kept"}}
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, cfg)
+
+	cfg = config{Tags: []string{}, Ports: []int{}, Labels: map[string]int{}, Weights: map[string]int{}, Preserved: []string{"kept"}}
+	err = ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, cfg)
+
+	os.Setenv("TEST_PORTS", "3|4")
+	os.Setenv("TEST_WEIGHTS", "c:3")
+	defer os.Clearenv()
+
+	cfg = config{}
+	err = ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, cfg.Ports)
+	assert.Equal(t, map[string]int{"c": 3}, cfg.Weights)
+}
+
+func TestReadFromEnvWithInheritedSeparator(t *testing.T) {
+	type Pool struct {
+		Hosts []string `env:"POOL_HOSTS"`
+		Ports []int    `env:"POOL_PORTS" env-separator:"|"`
+	}
+
+	type Cluster struct {
+		Nodes  []string       `env:"NODES"`
+		Labels map[string]int `env:"LABELS"`
+		Pool   Pool
+		Other  Pool `env-separator:","`
+	}
+
+	type config struct {
+		Default []string `env:"DEFAULT"`
+		Cluster Cluster  `env-separator:";"`
+	}
+
+	var env = map[string]string{
+		"DEFAULT":    "a,b",
+		"NODES":      "n1;n2",
+		"LABELS":     "a:1;b:2",
+		"POOL_HOSTS": "h1;h2",
+		"POOL_PORTS": "1|2",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	defer os.Clearenv()
+
+	var cfg config
+	err := ReadFromEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Default: []string{"a", "b"},
+		Cluster: Cluster{
+			Nodes:  []string{"n1", "n2"},
+			Labels: map[string]int{"a": 1, "b": 2},
+			Pool:   Pool{Hosts: []string{"h1", "h2"}, Ports: []int{1, 2}},
+			Other:  Pool{Hosts: []string{"h1;h2"}, Ports: []int{1, 2}},
+		},
+	}, cfg)
+}
@@ -0,0 +1,376 @@
+package libstandard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TagDump renames a field in the output of WriteConfig, the yaml and json tags still apply to parsing
+const TagDump = "dump"
+
+// WriteDefaultConfig writes a sample config-file for the structure in the given format ("yaml" or "json").
+// The values are taken from the env-default tags, fields without default are written with their zero values.
+// The current values of cfg are ignored. In YAML the environment variables of a field are added as comment.
+//
+// Example:
+//
+//	 err := config.WriteDefaultConfig(&Config{}, os.Stdout, "yaml")
+//	 if err != nil {
+//	     ...
+//	 }
+func WriteDefaultConfig(cfg interface{}, w io.Writer, format string) error {
+	cfgType := reflect.TypeOf(cfg)
+	if cfgType != nil && cfgType.Kind() == reflect.Ptr {
+		cfgType = cfgType.Elem()
+	}
+
+	if cfgType == nil || cfgType.Kind() != reflect.Struct {
+		return fmt.Errorf("wrong type %v", cfgType)
+	}
+
+	defaults := reflect.New(cfgType)
+	metaInfo, err := readStructMetadata(defaults.Interface())
+	if err != nil {
+		return err
+	}
+
+	for _, meta := range metaInfo {
+		if meta.defValue == nil {
+			continue
+		}
+
+		if err := parseValue(meta.fieldValue, *meta.defValue, meta.parseOptions); err != nil {
+			return fmt.Errorf("invalid default of field %q: %w", meta.fieldName, err)
+		}
+	}
+
+	switch ext := normalizeExt(format); ext {
+	case ".yaml", ".yml":
+		return writeYAMLWithEnvComments(w, defaults.Interface(), metaInfo)
+	case ".json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(defaults.Interface())
+	default:
+		return fmt.Errorf("file format '%s' doesn't supported by the writer", strings.TrimPrefix(ext, "."))
+	}
+}
+
+// WriteConfig writes the current values of cfg to the file at path, e.g. to persist the effective config after Read
+// for auditing. The format ("yaml" or "json") is inferred from the extension and missing parent directories are created.
+// The file is written atomically by renaming a temporary file, so readers never see a partially written config.
+// An existing file keeps its permissions, a new file is created with 0644.
+// Fields with a `dump:"name"` tag are written with that name instead of the one of their yaml or json tag.
+//
+// Example:
+//
+//	 err := config.WriteConfig(&cfg, "/var/lib/myapp/effective.yaml")
+//	 if err != nil {
+//	     ...
+//	 }
+func WriteConfig(cfg interface{}, path string) error {
+	var data []byte
+	var err error
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		data, err = marshalDumpYAML(cfg)
+	case ".json":
+		data, err = marshalDumpJSON(cfg)
+	default:
+		return fmt.Errorf("file format '%s' doesn't supported by the writer", strings.TrimPrefix(ext, "."))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	// the temporary file is created with 0600, the permissions of the replaced file are kept
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmpFile, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	/* #nosec */
+	defer os.Remove(tmpFile.Name())
+
+	if err := writeSynced(tmpFile, data, mode); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// writeSynced sets the permissions of the file, writes data and flushes it to the disk,
+// so the content is complete before the file is renamed
+func writeSynced(f *os.File, data []byte, mode os.FileMode) error {
+	if err := f.Chmod(mode); err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// marshalDumpYAML encodes the structure as YAML with the keys renamed by the dump tags
+func marshalDumpYAML(cfg interface{}) ([]byte, error) {
+	node := &yaml.Node{}
+	if err := node.Encode(cfg); err != nil {
+		return nil, err
+	}
+
+	renameDumpKeys(node, reflect.TypeOf(cfg), "yaml")
+	return yaml.Marshal(node)
+}
+
+// marshalDumpJSON encodes the structure as indented JSON with the keys renamed by the dump tags.
+// The JSON is decoded into a yaml.Node to rename the keys without losing their order.
+func marshalDumpJSON(cfg interface{}) ([]byte, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, err
+	}
+
+	renameDumpKeys(node, reflect.TypeOf(cfg), "json")
+
+	buf := &bytes.Buffer{}
+	if err := writeJSONNode(buf, node); err != nil {
+		return nil, err
+	}
+
+	indented := &bytes.Buffer{}
+	if err := json.Indent(indented, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
+
+// renameDumpKeys replaces the keys of the mapping-nodes with the dump tags of the matching fields,
+// the keys are matched against the names of the given tag ("yaml" or "json")
+func renameDumpKeys(node *yaml.Node, typeInfo reflect.Type, tag string) {
+	for typeInfo.Kind() == reflect.Ptr {
+		typeInfo = typeInfo.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, content := range node.Content {
+			renameDumpKeys(content, typeInfo, tag)
+		}
+
+	case yaml.SequenceNode:
+		if typeInfo.Kind() != reflect.Slice && typeInfo.Kind() != reflect.Array {
+			return
+		}
+
+		for _, item := range node.Content {
+			renameDumpKeys(item, typeInfo.Elem(), tag)
+		}
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if typeInfo.Kind() == reflect.Map {
+				renameDumpKeys(value, typeInfo.Elem(), tag)
+				continue
+			}
+
+			if typeInfo.Kind() != reflect.Struct {
+				return
+			}
+
+			field, _, ok := encodedField(typeInfo, tag, key.Value)
+			if !ok {
+				continue
+			}
+
+			if name := field.Tag.Get(TagDump); name != "" {
+				key.Value = name
+			}
+
+			renameDumpKeys(value, field.Type, tag)
+		}
+	}
+}
+
+// writeJSONNode writes the node, which was decoded from JSON, back as JSON keeping the order of the keys
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(buf, node.Content[0])
+
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeJSONNode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+
+	default:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+
+	return nil
+}
+
+// writeYAMLWithEnvComments writes the structure as YAML and adds the environment variables of the fields as comments
+func writeYAMLWithEnvComments(w io.Writer, cfg interface{}, metaInfo []structMeta) error {
+	node := &yaml.Node{}
+	if err := node.Encode(cfg); err != nil {
+		return err
+	}
+
+	metas := make(map[string]structMeta, len(metaInfo))
+	for _, meta := range metaInfo {
+		metas[meta.fieldPath] = meta
+	}
+
+	addEnvComments(node, reflect.TypeOf(cfg).Elem(), "", metas)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// addEnvComments annotates the keys of the mapping-node with the environment variables of the matching fields
+func addEnvComments(node *yaml.Node, typeInfo reflect.Type, path string, metas map[string]structMeta) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		field, parents, ok := encodedField(typeInfo, "yaml", key.Value)
+		if !ok {
+			continue
+		}
+
+		fieldPath := path + parents + field.Name
+		if meta, ok := metas[fieldPath]; ok && len(meta.envList) > 0 {
+			key.LineComment = "env: " + strings.Join(meta.envList, ", ")
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			addEnvComments(value, field.Type, fieldPath+".", metas)
+		}
+	}
+}
+
+// encodedField finds the exported field of the structure which is encoded with the given key,
+// using the names of the given tag ("yaml" or "json") and the default naming of the format.
+// The fields of structures which the format flattens into their parent (inlined by yaml, embedded without
+// name by json) are found as well, together with the path of their non-embedded parents, e.g. "Inline."
+func encodedField(typeInfo reflect.Type, tag, key string) (reflect.StructField, string, bool) {
+	for idx := 0; idx < typeInfo.NumField(); idx++ {
+		field := typeInfo.Field(idx)
+		name, opts, _ := strings.Cut(field.Tag.Get(tag), ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		inline := (tag == "yaml" && strings.Contains(","+opts+",", ",inline,")) || (tag == "json" && field.Anonymous && name == "")
+		if inline && fieldType.Kind() == reflect.Struct {
+			if nested, parents, ok := encodedField(fieldType, tag, key); ok {
+				if !field.Anonymous {
+					parents = field.Name + "." + parents
+				}
+				return nested, parents, true
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" && tag == "yaml" {
+			name = strings.ToLower(field.Name)
+		} else if name == "" {
+			name = field.Name
+		}
+
+		if name == key {
+			return field, "", true
+		}
+	}
+
+	return reflect.StructField{}, "", false
+}
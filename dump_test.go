@@ -0,0 +1,215 @@
+package libstandard
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dumpDatabase struct {
+	Host string `yaml:"host" json:"host" env:"HOST" env-default:"localhost"`
+	Port int    `yaml:"port" json:"port" env:"PORT,DB_PORT" env-default:"5432"`
+}
+
+type dumpConfig struct {
+	Name     string            `yaml:"name" json:"name" env:"NAME" env-default:"app"`
+	Debug    bool              `yaml:"debug" json:"debug"`
+	Tags     []string          `yaml:"tags" json:"tags" env:"TAGS" env-default:"a,b"`
+	Labels   map[string]string `yaml:"labels" json:"labels" env-default:"x:1"`
+	Database dumpDatabase      `yaml:"database" json:"database" env-prefix:"DB_"`
+}
+
+func TestWriteDefaultConfig(t *testing.T) {
+	expected := dumpConfig{
+		Name:     "app",
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]string{"x": "1"},
+		Database: dumpDatabase{Host: "localhost", Port: 5432},
+	}
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := WriteDefaultConfig(&dumpConfig{Name: "ignored"}, buf, format)
+			assert.NoError(t, err)
+
+			if format == "yaml" {
+				assert.Contains(t, buf.String(), "name: app # env: NAME\n")
+				assert.Contains(t, buf.String(), "  port: 5432 # env: DB_PORT, DB_DB_PORT\n")
+				assert.Contains(t, buf.String(), "debug: false\n")
+			}
+
+			tmpFile, err := os.CreateTemp(os.TempDir(), "*."+format)
+			if err != nil {
+				t.Fatal("cannot create temporary file:", err)
+			}
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write(buf.Bytes())
+			assert.NoError(t, err)
+
+			var cfg dumpConfig
+			err = ReadFromFile(&cfg, tmpFile.Name(), DefaultFileConfig{})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, cfg)
+		})
+	}
+
+	assert.Error(t, WriteDefaultConfig(dumpConfig{}, &bytes.Buffer{}, "xml"))
+	assert.Error(t, WriteDefaultConfig(42, &bytes.Buffer{}, "yaml"))
+	assert.Error(t, WriteDefaultConfig(nil, &bytes.Buffer{}, "yaml"))
+}
+
+func TestWriteConfig(t *testing.T) {
+	cfg := dumpConfig{
+		Name:     "app",
+		Debug:    true,
+		Tags:     []string{"x"},
+		Labels:   map[string]string{"k": "v"},
+		Database: dumpDatabase{Host: "db.local", Port: 1234},
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"effective.yaml", "nested/dir/effective.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			err := WriteConfig(&cfg, path)
+			assert.NoError(t, err)
+
+			var read dumpConfig
+			err = ReadFromFile(&read, path, DefaultFileConfig{})
+			assert.NoError(t, err)
+			assert.Equal(t, cfg, read)
+
+			info, err := os.Stat(path)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	path := filepath.Join(dir, "effective.yaml")
+	assert.NoError(t, os.Chmod(path, 0640))
+	assert.NoError(t, WriteConfig(&cfg, path))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	assert.Error(t, WriteConfig(&cfg, filepath.Join(dir, "effective.xml")))
+}
+
+func TestWriteConfigWithDumpTag(t *testing.T) {
+	type endpoint struct {
+		Host string `yaml:"host" json:"host" dump:"hostname"`
+		Port int    `yaml:"port" json:"port"`
+	}
+
+	type config struct {
+		Name      string              `yaml:"name" json:"name" dump:"service_name"`
+		Primary   endpoint            `yaml:"primary" json:"primary"`
+		Endpoints []endpoint          `yaml:"endpoints" json:"endpoints" dump:"targets"`
+		ByZone    map[string]endpoint `yaml:"by_zone" json:"by_zone"`
+	}
+
+	cfg := config{
+		Name:      "app",
+		Primary:   endpoint{Host: "a", Port: 1},
+		Endpoints: []endpoint{{Host: "b", Port: 2}},
+		ByZone:    map[string]endpoint{"eu": {Host: "c", Port: 3}},
+	}
+
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "effective.yaml")
+	assert.NoError(t, WriteConfig(&cfg, path))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `service_name: app
+primary:
+    hostname: a
+    port: 1
+targets:
+    - hostname: b
+      port: 2
+by_zone:
+    eu:
+        hostname: c
+        port: 3
+`, string(data))
+
+	path = filepath.Join(dir, "effective.json")
+	assert.NoError(t, WriteConfig(&cfg, path))
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "service_name": "app",
+  "primary": {
+    "hostname": "a",
+    "port": 1
+  },
+  "targets": [
+    {
+      "hostname": "b",
+      "port": 2
+    }
+  ],
+  "by_zone": {
+    "eu": {
+      "hostname": "c",
+      "port": 3
+    }
+  }
+}
+`, string(data))
+
+	path = filepath.Join(dir, "input.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("name: app\nprimary:\n  host: a\n  hostname: ignored\n"), 0600))
+	var read config
+	assert.NoError(t, ReadFromFile(&read, path, DefaultFileConfig{}))
+	assert.Equal(t, "app", read.Name)
+	assert.Equal(t, "a", read.Primary.Host)
+}
+
+func TestWriteConfigWithDumpTagInEmbeddedStructures(t *testing.T) {
+	type Meta struct {
+		Name string `yaml:"name" json:"name" dump:"display_name"`
+	}
+
+	type owner struct {
+		Team string `yaml:"team" json:"team" dump:"owning_team"`
+	}
+
+	type config struct {
+		Meta  `yaml:",inline"`
+		Owner owner `yaml:",inline" json:"owner"`
+		Port  int   `yaml:"port" json:"port" dump:"listen_port"`
+	}
+
+	cfg := config{Meta: Meta{Name: "app"}, Owner: owner{Team: "core"}, Port: 8080}
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "effective.yaml")
+	assert.NoError(t, WriteConfig(&cfg, path))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "display_name: app\nowning_team: core\nlisten_port: 8080\n", string(data))
+
+	path = filepath.Join(dir, "effective.json")
+	assert.NoError(t, WriteConfig(&cfg, path))
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "display_name": "app",
+  "owner": {
+    "owning_team": "core"
+  },
+  "listen_port": 8080
+}
+`, string(data))
+}
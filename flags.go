@@ -1,6 +1,175 @@
 package libstandard
 
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
 const (
 	Verbosity = "verbosity"
 	Config    = "config"
 )
+
+// BindFlags registers a flag on the cobra-command for every field of the structure with a "flag" tag.
+// The flag-type is derived from the field-type and the "env-default" tag is used as default value,
+// so the flag definitions can't get out of sync with the config-structure.
+// A shorthand can be given as second value of the tag, e.g. `flag:"port,p"`.
+//
+// Example:
+//
+//	 type Config struct {
+//	 	Port  int      `flag:"port,p" env-default:"5432"`
+//	 	Hosts []string `flag:"hosts"`
+//	 }
+//
+//	 err := config.BindFlags(cmd, &Config{})
+//	 if err != nil {
+//	     ...
+//	 }
+func BindFlags(cmd *cobra.Command, cfg interface{}) error {
+	metaInfo, err := readStructMetadata(cfg)
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	for _, meta := range metaInfo {
+		if meta.flagName == "" {
+			continue
+		}
+
+		if flags.Lookup(meta.flagName) != nil {
+			return fmt.Errorf("flag %q of field %q is already defined", meta.flagName, meta.fieldName)
+		}
+
+		if meta.flagShorthand != "" && flags.ShorthandLookup(meta.flagShorthand) != nil {
+			return fmt.Errorf("flag shorthand %q of field %q is already defined", meta.flagShorthand, meta.fieldName)
+		}
+
+		def := reflect.New(meta.fieldValue.Type()).Elem()
+		if meta.defValue != nil {
+			if err := parseValue(def, *meta.defValue, meta.parseOptions); err != nil {
+				return fmt.Errorf("invalid default of field %q: %w", meta.fieldName, err)
+			}
+		}
+
+		if err := bindFlag(cmd, meta.flagName, meta.flagShorthand, def, meta.fieldName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindFlag registers a flag with the type and the default of the given value
+func bindFlag(cmd *cobra.Command, name, shorthand string, def reflect.Value, fieldName string) error {
+	flags := cmd.Flags()
+	usage := fmt.Sprintf("Value of %s", fieldName)
+
+	if d, ok := def.Interface().(time.Duration); ok {
+		flags.DurationP(name, shorthand, d, usage)
+		return nil
+	}
+
+	switch def.Kind() {
+	case reflect.String:
+		flags.StringP(name, shorthand, def.String(), usage)
+	case reflect.Bool:
+		value := boolFlagValue(def.Bool())
+		flags.VarPF(&value, name, shorthand, usage).NoOptDefVal = "true"
+	case reflect.Int:
+		flags.IntP(name, shorthand, int(def.Int()), usage)
+	case reflect.Int8:
+		flags.Int8P(name, shorthand, int8(def.Int()), usage)
+	case reflect.Int16:
+		flags.Int16P(name, shorthand, int16(def.Int()), usage)
+	case reflect.Int32:
+		flags.Int32P(name, shorthand, int32(def.Int()), usage)
+	case reflect.Int64:
+		flags.Int64P(name, shorthand, def.Int(), usage)
+	case reflect.Uint:
+		flags.UintP(name, shorthand, uint(def.Uint()), usage)
+	case reflect.Uint8:
+		flags.Uint8P(name, shorthand, uint8(def.Uint()), usage)
+	case reflect.Uint16:
+		flags.Uint16P(name, shorthand, uint16(def.Uint()), usage)
+	case reflect.Uint32:
+		flags.Uint32P(name, shorthand, uint32(def.Uint()), usage)
+	case reflect.Uint64:
+		flags.Uint64P(name, shorthand, def.Uint(), usage)
+	case reflect.Float32:
+		flags.Float32P(name, shorthand, float32(def.Float()), usage)
+	case reflect.Float64:
+		flags.Float64P(name, shorthand, def.Float(), usage)
+	case reflect.Slice:
+		return bindSliceFlag(cmd, name, shorthand, def, fieldName)
+	default:
+		return fmt.Errorf("unsupported flag type %s of field %q", def.Type(), fieldName)
+	}
+
+	return nil
+}
+
+// boolFlagValue is a bool flag-value which accepts the same values as environment variables,
+// e.g. "--enabled=yes" besides the values of strconv.ParseBool
+type boolFlagValue bool
+
+func (b *boolFlagValue) Set(s string) error {
+	v, err := parseBool(s)
+	if err != nil {
+		return err
+	}
+	*b = boolFlagValue(v)
+	return nil
+}
+
+func (b *boolFlagValue) String() string {
+	return strconv.FormatBool(bool(*b))
+}
+
+func (b *boolFlagValue) Type() string {
+	return "bool"
+}
+
+// bindSliceFlag registers a slice-flag with the element-type and the default of the given value
+func bindSliceFlag(cmd *cobra.Command, name, shorthand string, def reflect.Value, fieldName string) error {
+	flags := cmd.Flags()
+	usage := fmt.Sprintf("Value of %s", fieldName)
+	elemType := def.Type().Elem()
+
+	convert := func(target interface{}) interface{} {
+		targetType := reflect.TypeOf(target)
+		values := reflect.MakeSlice(targetType, def.Len(), def.Len())
+		for i := 0; i < def.Len(); i++ {
+			values.Index(i).Set(def.Index(i).Convert(targetType.Elem()))
+		}
+		return values.Interface()
+	}
+
+	switch elemType.Kind() {
+	case reflect.String:
+		flags.StringSliceP(name, shorthand, convert([]string{}).([]string), usage)
+	case reflect.Bool:
+		flags.BoolSliceP(name, shorthand, convert([]bool{}).([]bool), usage)
+	case reflect.Int:
+		flags.IntSliceP(name, shorthand, convert([]int{}).([]int), usage)
+	case reflect.Int32:
+		flags.Int32SliceP(name, shorthand, convert([]int32{}).([]int32), usage)
+	case reflect.Int64:
+		flags.Int64SliceP(name, shorthand, convert([]int64{}).([]int64), usage)
+	case reflect.Uint:
+		flags.UintSliceP(name, shorthand, convert([]uint{}).([]uint), usage)
+	case reflect.Float32:
+		flags.Float32SliceP(name, shorthand, convert([]float32{}).([]float32), usage)
+	case reflect.Float64:
+		flags.Float64SliceP(name, shorthand, convert([]float64{}).([]float64), usage)
+	default:
+		return fmt.Errorf("unsupported flag type %s of field %q", def.Type(), fieldName)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package libstandard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindFlags(t *testing.T) {
+	type config struct {
+		Host    string        `flag:"host" env-default:"localhost"`
+		Port    int32         `flag:"port" env-default:"5432"`
+		Debug   bool          `flag:"debug"`
+		Ratio   float64       `flag:"ratio" env-default:"0.5"`
+		Names   []string      `flag:"names" env-default:"a,b"`
+		Ids     []int         `flag:"ids"`
+		NoFlag  string        `env:"NO_FLAG"`
+		Timeout uint          `flag:"timeout" env-default:"30"`
+		Wait    time.Duration `flag:"wait" env-default:"1m"`
+	}
+
+	cmd := &cobra.Command{}
+	err := BindFlags(cmd, &config{})
+	assert.NoError(t, err)
+
+	expected := map[string]struct {
+		typ string
+		def string
+	}{
+		"host":    {"string", "localhost"},
+		"port":    {"int32", "5432"},
+		"debug":   {"bool", "false"},
+		"ratio":   {"float64", "0.5"},
+		"names":   {"stringSlice", "[a,b]"},
+		"ids":     {"intSlice", "[]"},
+		"timeout": {"uint", "30"},
+		"wait":    {"duration", "1m0s"},
+	}
+
+	for name, want := range expected {
+		flag := cmd.Flags().Lookup(name)
+		if assert.NotNil(t, flag, name) {
+			assert.Equal(t, want.typ, flag.Value.Type(), name)
+			assert.Equal(t, want.def, flag.DefValue, name)
+			assert.Contains(t, flag.Usage, "Value of ")
+		}
+	}
+	assert.Nil(t, cmd.Flags().Lookup("no-flag"))
+
+	err = cmd.ParseFlags([]string{"--port", "1000", "--debug", "--names", "x,y,z", "--ids", "1,2", "--wait", "5s"})
+	assert.NoError(t, err)
+
+	var cfg config
+	err = ReadFromFlags(&cfg, cmd.Flags())
+	assert.NoError(t, err)
+
+	want := config{Host: "localhost", Port: 1000, Debug: true, Ratio: 0.5, Names: []string{"x", "y", "z"}, Ids: []int{1, 2}, Timeout: 30, Wait: 5 * time.Second}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("wrong data %v, want %v", cfg, want)
+	}
+}
+
+func TestBindFlagsWithExtendedBool(t *testing.T) {
+	type config struct {
+		Enabled bool `flag:"enabled"`
+		Verbose bool `flag:"verbose,v" env-default:"on"`
+	}
+
+	tests := []struct {
+		args    []string
+		want    config
+		wantErr bool
+	}{
+		{args: []string{}, want: config{Verbose: true}},
+		{args: []string{"--enabled"}, want: config{Enabled: true, Verbose: true}},
+		{args: []string{"--enabled=yes", "--verbose=off"}, want: config{Enabled: true}},
+		{args: []string{"--enabled=Enabled", "-v=false"}, want: config{Enabled: true}},
+		{args: []string{"--enabled=maybe"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			cmd := &cobra.Command{}
+			assert.NoError(t, BindFlags(cmd, &config{}))
+			assert.Equal(t, "true", cmd.Flags().Lookup("verbose").DefValue)
+
+			err := cmd.ParseFlags(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			enabled, err := cmd.Flags().GetBool("enabled")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.Enabled, enabled)
+
+			var cfg config
+			assert.NoError(t, ReadFromFlags(&cfg, cmd.Flags()))
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestBindFlagsErrors(t *testing.T) {
+	type duplicate struct {
+		Host  string `flag:"host"`
+		Other string `flag:"host"`
+	}
+	assert.Error(t, BindFlags(&cobra.Command{}, &duplicate{}))
+
+	type invalidDefault struct {
+		Port int `flag:"port" env-default:"abc"`
+	}
+	assert.Error(t, BindFlags(&cobra.Command{}, &invalidDefault{}))
+
+	type unsupported struct {
+		Labels map[string]string `flag:"labels"`
+	}
+	assert.Error(t, BindFlags(&cobra.Command{}, &unsupported{}))
+
+	assert.Error(t, BindFlags(&cobra.Command{}, 42))
+}
+
+func TestBindFlagsWithShorthand(t *testing.T) {
+	type config struct {
+		Port  int      `flag:"port,p" env-default:"5432"`
+		Names []string `flag:"names,n"`
+		Host  string   `flag:"host"`
+	}
+
+	cmd := &cobra.Command{}
+	err := BindFlags(cmd, &config{})
+	assert.NoError(t, err)
+	assert.Equal(t, "port", cmd.Flags().ShorthandLookup("p").Name)
+	assert.Equal(t, "names", cmd.Flags().ShorthandLookup("n").Name)
+	assert.Equal(t, "", cmd.Flags().Lookup("host").Shorthand)
+
+	err = cmd.ParseFlags([]string{"-p", "1000", "-n", "a,b"})
+	assert.NoError(t, err)
+
+	var cfg config
+	err = ReadFromFlags(&cfg, cmd.Flags())
+	assert.NoError(t, err)
+	assert.Equal(t, config{Port: 1000, Names: []string{"a", "b"}}, cfg)
+
+	type invalid struct {
+		Port int `flag:"port,po"`
+	}
+	assert.Error(t, BindFlags(&cobra.Command{}, &invalid{}))
+	assert.Error(t, ReadFromFlags(&invalid{}, cmd.Flags()))
+
+	type duplicate struct {
+		Port int    `flag:"port,p"`
+		Path string `flag:"path,p"`
+	}
+	assert.Error(t, BindFlags(&cobra.Command{}, &duplicate{}))
+}
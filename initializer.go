@@ -2,27 +2,69 @@ package libstandard
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 
 	"github.com/iancoleman/strcase"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 // DefaultInitializer loads the config and initializes the logging.
 // This assumes that there are "config" and "verbosity" flags present on the cobra-command.
+// The config-file is searched as "<name>.yaml" in the working-directory and in "~/.config/<name>".
 func DefaultInitializer(cfg interface{}, cmd *cobra.Command, name string) error {
-	config, err := cmd.Flags().GetString(Config)
+	return DefaultInitializerWithWriter(cfg, cmd, name, os.Stdout)
+}
+
+// DefaultInitializerWithWriter works like DefaultInitializer, but writes the log to out instead of os.Stdout,
+// e.g. to os.Stderr for tools which write their machine-readable output to stdout.
+func DefaultInitializerWithWriter(cfg interface{}, cmd *cobra.Command, name string, out io.Writer) error {
+	return initialize(cfg, cmd, out, defaultInitializerFileConfig(name))
+}
+
+// DefaultInitializerWithConfig works like DefaultInitializer, but searches the config-file with the given DefaultFileConfig.
+func DefaultInitializerWithConfig(cfg interface{}, cmd *cobra.Command, name string, fileCfg DefaultFileConfig) error {
+	return initialize(cfg, cmd, os.Stdout, fileCfg)
+}
+
+func defaultInitializerFileConfig(name string) DefaultFileConfig {
+	return DefaultFileConfig{Name: name, Extensions: []string{"yaml"}, Paths: []string{".", "~/.config/" + name}}
+}
+
+// DefaultInitializerReturningLogger loads the config like DefaultInitializer, but returns a new logger configured
+// with the verbosity instead of setting up the global logger of logrus.
+func DefaultInitializerReturningLogger(cfg interface{}, cmd *cobra.Command, name string) (*logrus.Logger, error) {
+	verbosity, err := readInitializerConfig(cfg, cmd, defaultInitializerFileConfig(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogger(os.Stdout, verbosity)
+}
+
+func initialize(cfg interface{}, cmd *cobra.Command, out io.Writer, fileCfg DefaultFileConfig) error {
+	verbosity, err := readInitializerConfig(cfg, cmd, fileCfg)
 	if err != nil {
 		return err
 	}
 
-	err = Read(cfg, cmd.Flags(), config, DefaultFileConfig{Name: name, Extensions: []string{"yaml"}, Paths: []string{".", "~/.config/" + name}})
+	return SetupLogging(out, verbosity)
+}
+
+// readInitializerConfig reads the config with the file of the "config" flag and returns its verbosity
+func readInitializerConfig(cfg interface{}, cmd *cobra.Command, fileCfg DefaultFileConfig) (string, error) {
+	config, err := cmd.Flags().GetString(Config)
+	if err != nil {
+		return "", err
+	}
+
+	err = Read(cfg, cmd.Flags(), config, fileCfg)
 	if err != nil {
-		return fmt.Errorf("An error occurred while reading the config! %w", err)
+		return "", fmt.Errorf("An error occurred while reading the config! %w", err)
 	}
 
 	x := reflect.ValueOf(cfg).Elem()
-	verbosity := x.FieldByName(strcase.ToCamel(Verbosity)).String()
-	return SetupLogging(os.Stdout, verbosity)
+	return x.FieldByName(strcase.ToCamel(Verbosity)).String(), nil
 }
@@ -0,0 +1,81 @@
+package libstandard
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type initializerConfig struct {
+	Verbosity string `json:"verbosity" yaml:"verbosity" flag:"verbosity"`
+	Host      string `json:"host" yaml:"host"`
+}
+
+func newInitializerCommand(t *testing.T, args ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	AddConfigFlag(cmd)
+	AddVerbosityFlag(cmd)
+	assert.NoError(t, cmd.ParseFlags(args))
+	return cmd
+}
+
+func TestDefaultInitializerWithConfig(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "myapp.json"), []byte(`{"verbosity": "debug", "host": "example.com"}`), 0600)
+	assert.NoError(t, err)
+
+	var cfg initializerConfig
+	err = DefaultInitializerWithConfig(&cfg, newInitializerCommand(t), "myapp", DefaultFileConfig{Name: "myapp", Extensions: []string{"json"}, Paths: []string{dir}})
+	assert.NoError(t, err)
+	assert.Equal(t, initializerConfig{Verbosity: "debug", Host: "example.com"}, cfg)
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+
+	cfg = initializerConfig{}
+	err = DefaultInitializerWithConfig(&cfg, newInitializerCommand(t), "myapp", DefaultFileConfig{Name: "myapp", Extensions: []string{"yaml"}, Paths: []string{dir}})
+	assert.NoError(t, err)
+	assert.Equal(t, initializerConfig{Verbosity: "info"}, cfg)
+	assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+}
+
+func TestDefaultInitializer(t *testing.T) {
+	var cfg initializerConfig
+	err := DefaultInitializer(&cfg, newInitializerCommand(t), "libstandard-test-nonexistent")
+	assert.NoError(t, err)
+	assert.Equal(t, initializerConfig{Verbosity: "info"}, cfg)
+
+	err = DefaultInitializer(&cfg, &cobra.Command{}, "libstandard-test-nonexistent")
+	assert.Error(t, err)
+}
+
+func TestDefaultInitializerWithWriter(t *testing.T) {
+	defer logrus.SetOutput(os.Stdout)
+
+	buf := &bytes.Buffer{}
+	var cfg initializerConfig
+	err := DefaultInitializerWithWriter(&cfg, newInitializerCommand(t, "--verbosity", "debug"), "libstandard-test-nonexistent", buf)
+	assert.NoError(t, err)
+	assert.Equal(t, initializerConfig{Verbosity: "debug"}, cfg)
+
+	logrus.Debug("captured message")
+	assert.Contains(t, buf.String(), "captured message")
+}
+
+func TestDefaultInitializerReturningLogger(t *testing.T) {
+	err := SetupLogging(os.Stdout, "info")
+	assert.NoError(t, err)
+
+	var cfg initializerConfig
+	logger, err := DefaultInitializerReturningLogger(&cfg, newInitializerCommand(t, "--verbosity", "debug"), "libstandard-test-nonexistent")
+	assert.NoError(t, err)
+	assert.Equal(t, initializerConfig{Verbosity: "debug"}, cfg)
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+	assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+
+	_, err = DefaultInitializerReturningLogger(&cfg, newInitializerCommand(t, "--verbosity", "invalid"), "libstandard-test-nonexistent")
+	assert.Error(t, err)
+}
@@ -1,24 +1,261 @@
 package libstandard
 
 import (
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// LogLevelEnv is the environment variable consulted for the log level if none is given
+const LogLevelEnv = "LOG_LEVEL"
+
+// LoggingOptions holds optional settings for SetupLoggingWithOptions.
+// Unset options leave the current settings of the standard logger and its formatter untouched.
+type LoggingOptions struct {
+	// ReportCaller adds the calling file:line and function to each log-entry
+	ReportCaller bool
+	// TimestampFormat is the time-layout used by the text- and json-formatter (e.g. time.RFC3339Nano).
+	// An empty format keeps the current one.
+	TimestampFormat string
+	// DisableColors disables the colors of the text-formatter, e.g. when the log is redirected to a file.
+	// If neither DisableColors nor ForceColors is set, the current color settings are kept, which use colors
+	// by default if the output is a terminal. It takes precedence over ForceColors.
+	DisableColors bool
+	// ForceColors enables the colors of the text-formatter even if the output is no terminal
+	ForceColors bool
+	// Hooks are added to the hooks of the standard logger, e.g. to ship error-logs to Sentry.
+	// They replace the hooks added by a previous call, so setting up the logging again doesn't add them twice.
+	// Hooks registered by other means are kept.
+	Hooks []logrus.Hook
+}
+
 //SetupLogging set the log output as the log level
+//An empty level falls back to the LOG_LEVEL environment variable and then to "info"
 func SetupLogging(out io.Writer, level string) error {
+	return SetupLoggingWithOptions(out, level, LoggingOptions{})
+}
+
+// SetupLoggingWithOptions sets the log output and the log level like SetupLogging and applies the given options.
+func SetupLoggingWithOptions(out io.Writer, level string, opts LoggingOptions) error {
 	logrus.SetOutput(out)
-	lvl, err := logrus.ParseLevel(level)
+	lvl, err := parseLevel(level)
 	if err != nil {
 		return err
 	}
 
 	logrus.SetLevel(lvl)
+	if opts.ReportCaller {
+		logrus.SetReportCaller(true)
+	}
+	removeSplitOutputHooks()
+
+	if opts.Hooks != nil {
+		replaceOptionHooks(opts.Hooks)
+	}
+
+	switch formatter := logrus.StandardLogger().Formatter.(type) {
+	case *logrus.TextFormatter:
+		if opts.TimestampFormat != "" {
+			formatter.TimestampFormat = opts.TimestampFormat
+		}
+		if opts.DisableColors || opts.ForceColors {
+			formatter.DisableColors = opts.DisableColors
+			formatter.ForceColors = opts.ForceColors
+		}
+	case *logrus.JSONFormatter:
+		if opts.TimestampFormat != "" {
+			formatter.TimestampFormat = opts.TimestampFormat
+		}
+	}
+
+	return nil
+}
+
+// SetupSplitLogging sets the log level like SetupLogging and routes the log by level: warnings and more severe
+// entries are written to stderr, all others to stdout. The default output is discarded. Calling SetupLogging
+// or one of its variants afterwards removes the routing again.
+//
+// Example:
+//
+//	 err := libstandard.SetupSplitLogging(os.Stdout, os.Stderr, "info")
+func SetupSplitLogging(stdout, stderr io.Writer, level string) error {
+	// validate the level first, so an invalid one leaves the current output untouched
+	if _, err := parseLevel(level); err != nil {
+		return err
+	}
+
+	if err := SetupLogging(io.Discard, level); err != nil {
+		return err
+	}
+
+	logrus.AddHook(&splitOutputHook{stdout: stdout, stderr: stderr})
 	return nil
 }
 
+// splitOutputHook writes the formatted entries to stderr or stdout depending on their level
+type splitOutputHook struct {
+	mu     sync.Mutex
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (h *splitOutputHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *splitOutputHook) Fire(entry *logrus.Entry) error {
+	line, err := entry.Bytes()
+	if err != nil {
+		return err
+	}
+
+	out := h.stdout
+	if entry.Level <= logrus.WarnLevel {
+		out = h.stderr
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = out.Write(line)
+	return err
+}
+
+// removeSplitOutputHooks removes the hooks added by SetupSplitLogging from the standard logger
+func removeSplitOutputHooks() {
+	removeHooks(func(hook logrus.Hook) bool {
+		_, ok := hook.(*splitOutputHook)
+		return ok
+	})
+}
+
+var (
+	optionHooksMu sync.Mutex
+	// optionHooks are the hooks added by the last call of SetupLoggingWithOptions with hooks
+	optionHooks []logrus.Hook
+)
+
+// replaceOptionHooks replaces the hooks added by a previous call of SetupLoggingWithOptions with the given ones
+func replaceOptionHooks(hooks []logrus.Hook) {
+	optionHooksMu.Lock()
+	defer optionHooksMu.Unlock()
+
+	removeHooks(func(hook logrus.Hook) bool {
+		for _, added := range optionHooks {
+			if sameHook(hook, added) {
+				return true
+			}
+		}
+		return false
+	})
+
+	for _, hook := range hooks {
+		logrus.AddHook(hook)
+	}
+	optionHooks = append([]logrus.Hook(nil), hooks...)
+}
+
+// removeHooks removes the hooks matching remove from the standard logger
+func removeHooks(remove func(logrus.Hook) bool) {
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range logrus.StandardLogger().Hooks {
+		for _, hook := range levelHooks {
+			if !remove(hook) {
+				hooks[level] = append(hooks[level], hook)
+			}
+		}
+	}
+
+	logrus.StandardLogger().ReplaceHooks(hooks)
+}
+
+// sameHook reports whether both hooks are equal, hooks of non-comparable types are never equal
+func sameHook(a, b logrus.Hook) bool {
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() && a == b
+}
+
+// NewLogger returns a new logger writing to out with the level parsed like SetupLogging,
+// leaving the global logger of logrus untouched.
+func NewLogger(out io.Writer, level string) (*logrus.Logger, error) {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(out)
+	logger.SetLevel(lvl)
+	return logger, nil
+}
+
+// SetupLoggingMulti sets the log level like SetupLogging and writes the log to all given writers, e.g. to the console
+// and to a file at the same time. As the formatter of logrus is global, all writers receive the same format.
+func SetupLoggingMulti(level string, writers ...io.Writer) error {
+	return SetupLogging(io.MultiWriter(writers...), level)
+}
+
+// SetupFileLogging sets the log level and writes the log to the file at path, creating missing parent directories.
+// The file is rotated when it grows beyond maxSizeMB megabytes (lumberjack's default of 100 if <= 0).
+// The returned closer should be closed on shutdown to flush and release the file.
+func SetupFileLogging(path string, level string, maxSizeMB int) (io.Closer, error) {
+	if _, err := parseLevel(level); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+
+	out := &lumberjack.Logger{
+		Filename: path,
+		MaxSize:  maxSizeMB,
+	}
+
+	if err := SetupLogging(out, level); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// levelAliases maps alternative level names of other tools to the logrus names
+var levelAliases = map[string]string{
+	"verbose": "debug",
+}
+
+// parseLevel parses the log level, falling back to LOG_LEVEL and "info" if it is empty.
+// Besides the logrus names it accepts the numeric levels "0" (panic) to "6" (trace) and the levelAliases.
+func parseLevel(level string) (logrus.Level, error) {
+	if level == "" {
+		level = os.Getenv(LogLevelEnv)
+	}
+
+	if level == "" {
+		return logrus.InfoLevel, nil
+	}
+
+	if n, err := strconv.Atoi(level); err == nil {
+		if n < int(logrus.PanicLevel) || n > int(logrus.TraceLevel) {
+			return 0, fmt.Errorf("not a valid logrus Level: %q", level)
+		}
+		return logrus.Level(n), nil
+	}
+
+	if alias, ok := levelAliases[strings.ToLower(level)]; ok {
+		level = alias
+	}
+
+	return logrus.ParseLevel(level)
+}
+
 func AddVerbosityFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(Verbosity, "v", logrus.InfoLevel.String(), "Log-level (debug, info, warn, error, fatal, panic)")
 }
@@ -1,8 +1,13 @@
 package libstandard
 
 import (
+	"bytes"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -20,6 +25,309 @@ func TestSetupLogging(t *testing.T) {
 	assert.Equal(t, "info", logrus.GetLevel().String())
 }
 
+func TestSetupLoggingWithLevelAliases(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    logrus.Level
+		wantErr bool
+	}{
+		{level: "5", want: logrus.DebugLevel},
+		{level: "0", want: logrus.PanicLevel},
+		{level: "6", want: logrus.TraceLevel},
+		{level: "verbose", want: logrus.DebugLevel},
+		{level: "VERBOSE", want: logrus.DebugLevel},
+		{level: "trace", want: logrus.TraceLevel},
+		{level: "7", wantErr: true},
+		{level: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			err := SetupLogging(os.Stdout, "info")
+			assert.Nil(t, err)
+
+			err = SetupLogging(os.Stdout, tt.level)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, logrus.GetLevel())
+		})
+	}
+}
+
+func TestSetupLoggingWithEmptyLevel(t *testing.T) {
+	defer os.Unsetenv(LogLevelEnv)
+
+	os.Setenv(LogLevelEnv, "warn")
+	err := SetupLogging(os.Stdout, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "warning", logrus.GetLevel().String())
+
+	err = SetupLogging(os.Stdout, "debug")
+	assert.Nil(t, err)
+	assert.Equal(t, "debug", logrus.GetLevel().String())
+
+	os.Unsetenv(LogLevelEnv)
+	err = SetupLogging(os.Stdout, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "info", logrus.GetLevel().String())
+
+	os.Setenv(LogLevelEnv, "unknown")
+	err = SetupLogging(os.Stdout, "")
+	assert.NotNil(t, err)
+
+	err = SetupLogging(os.Stdout, "invalid")
+	assert.NotNil(t, err)
+}
+
+func TestSetupLoggingWithOptions(t *testing.T) {
+	defer logrus.SetReportCaller(false)
+
+	err := SetupLoggingWithOptions(os.Stdout, "debug", LoggingOptions{ReportCaller: true})
+	assert.Nil(t, err)
+	assert.True(t, logrus.StandardLogger().ReportCaller)
+	assert.Equal(t, "debug", logrus.GetLevel().String())
+
+	err = SetupLogging(os.Stdout, "info")
+	assert.Nil(t, err)
+	assert.True(t, logrus.StandardLogger().ReportCaller)
+}
+
+func TestSetupLoggingKeepsSettings(t *testing.T) {
+	defer logrus.SetFormatter(&logrus.TextFormatter{})
+	defer logrus.SetReportCaller(false)
+
+	logrus.SetFormatter(&logrus.TextFormatter{ForceColors: true, TimestampFormat: time.RFC3339Nano})
+	logrus.SetReportCaller(true)
+
+	err := SetupLogging(os.Stdout, "info")
+	assert.Nil(t, err)
+	formatter := logrus.StandardLogger().Formatter.(*logrus.TextFormatter)
+	assert.True(t, formatter.ForceColors)
+	assert.False(t, formatter.DisableColors)
+	assert.Equal(t, time.RFC3339Nano, formatter.TimestampFormat)
+	assert.True(t, logrus.StandardLogger().ReportCaller)
+
+	err = SetupLoggingWithOptions(os.Stdout, "info", LoggingOptions{DisableColors: true})
+	assert.Nil(t, err)
+	assert.True(t, formatter.DisableColors)
+	assert.False(t, formatter.ForceColors)
+	assert.Equal(t, time.RFC3339Nano, formatter.TimestampFormat)
+	assert.True(t, logrus.StandardLogger().ReportCaller)
+}
+
+func TestSetupLoggingWithTimestampFormat(t *testing.T) {
+	defer logrus.SetFormatter(&logrus.TextFormatter{})
+
+	err := SetupLoggingWithOptions(os.Stdout, "info", LoggingOptions{TimestampFormat: time.RFC3339Nano})
+	assert.Nil(t, err)
+	assert.Equal(t, time.RFC3339Nano, logrus.StandardLogger().Formatter.(*logrus.TextFormatter).TimestampFormat)
+
+	err = SetupLogging(os.Stdout, "info")
+	assert.Nil(t, err)
+	assert.Equal(t, time.RFC3339Nano, logrus.StandardLogger().Formatter.(*logrus.TextFormatter).TimestampFormat)
+
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	err = SetupLoggingWithOptions(os.Stdout, "info", LoggingOptions{TimestampFormat: time.Kitchen})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Kitchen, logrus.StandardLogger().Formatter.(*logrus.JSONFormatter).TimestampFormat)
+}
+
+func TestSetupLoggingWithColors(t *testing.T) {
+	defer logrus.SetFormatter(&logrus.TextFormatter{})
+
+	err := SetupLoggingWithOptions(os.Stdout, "info", LoggingOptions{DisableColors: true})
+	assert.Nil(t, err)
+	formatter := logrus.StandardLogger().Formatter.(*logrus.TextFormatter)
+	assert.True(t, formatter.DisableColors)
+	assert.False(t, formatter.ForceColors)
+
+	err = SetupLoggingWithOptions(os.Stdout, "info", LoggingOptions{ForceColors: true})
+	assert.Nil(t, err)
+	assert.False(t, formatter.DisableColors)
+	assert.True(t, formatter.ForceColors)
+
+	err = SetupLogging(os.Stdout, "info")
+	assert.Nil(t, err)
+	assert.False(t, formatter.DisableColors)
+	assert.True(t, formatter.ForceColors)
+}
+
+type recordingHook struct {
+	entries []*logrus.Entry
+}
+
+func (h *recordingHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.ErrorLevel}
+}
+
+func (h *recordingHook) Fire(entry *logrus.Entry) error {
+	h.entries = append(h.entries, entry)
+	return nil
+}
+
+func TestSetupLoggingWithHooks(t *testing.T) {
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	defer logrus.SetOutput(os.Stdout)
+
+	hook := &recordingHook{}
+	err := SetupLoggingWithOptions(io.Discard, "info", LoggingOptions{Hooks: []logrus.Hook{hook}})
+	assert.Nil(t, err)
+
+	logrus.Info("ignored")
+	logrus.Error("shipped")
+	assert.Len(t, hook.entries, 1)
+	assert.Equal(t, "shipped", hook.entries[0].Message)
+	assert.Equal(t, logrus.ErrorLevel, hook.entries[0].Level)
+}
+
+func TestSetupLoggingWithHooksTwice(t *testing.T) {
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	defer logrus.SetOutput(os.Stdout)
+
+	other := &recordingHook{}
+	logrus.AddHook(other)
+
+	hook := &recordingHook{}
+	for i := 0; i < 2; i++ {
+		err := SetupLoggingWithOptions(io.Discard, "info", LoggingOptions{Hooks: []logrus.Hook{hook}})
+		assert.Nil(t, err)
+	}
+
+	logrus.Error("once")
+	assert.Len(t, hook.entries, 1)
+	assert.Len(t, other.entries, 1)
+
+	replacement := &recordingHook{}
+	err := SetupLoggingWithOptions(io.Discard, "info", LoggingOptions{Hooks: []logrus.Hook{replacement}})
+	assert.Nil(t, err)
+	err = SetupLogging(io.Discard, "info")
+	assert.Nil(t, err)
+
+	logrus.Error("replaced")
+	assert.Len(t, hook.entries, 1)
+	assert.Len(t, replacement.entries, 1)
+	assert.Len(t, other.entries, 2)
+}
+
+func TestSetupSplitLogging(t *testing.T) {
+	defer logrus.SetOutput(os.Stdout)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := SetupSplitLogging(stdout, stderr, "info")
+	assert.Nil(t, err)
+	assert.Equal(t, io.Discard, logrus.StandardLogger().Out)
+
+	logrus.Info("info message")
+	logrus.Warn("warn message")
+	logrus.Error("error message")
+	logrus.Debug("filtered")
+
+	assert.Contains(t, stdout.String(), "info message")
+	assert.NotContains(t, stdout.String(), "warn message")
+	assert.NotContains(t, stdout.String(), "error message")
+	assert.Contains(t, stderr.String(), "warn message")
+	assert.Contains(t, stderr.String(), "error message")
+	assert.NotContains(t, stderr.String(), "info message")
+	assert.NotContains(t, stdout.String()+stderr.String(), "filtered")
+
+	err = SetupSplitLogging(stdout, stderr, "info")
+	assert.Nil(t, err)
+	stdout.Reset()
+	logrus.Info("once")
+	assert.Equal(t, 1, strings.Count(stdout.String(), "once"))
+
+	err = SetupLogging(io.Discard, "info")
+	assert.Nil(t, err)
+	stdout.Reset()
+	logrus.Info("removed")
+	assert.Empty(t, stdout.String())
+
+	err = SetupSplitLogging(stdout, stderr, "info")
+	assert.Nil(t, err)
+	err = SetupSplitLogging(&bytes.Buffer{}, &bytes.Buffer{}, "invalid")
+	assert.NotNil(t, err)
+
+	stdout.Reset()
+	stderr.Reset()
+	logrus.Info("still routed")
+	logrus.Error("still routed error")
+	assert.Contains(t, stdout.String(), "still routed")
+	assert.Contains(t, stderr.String(), "still routed error")
+	assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+
+	out := &bytes.Buffer{}
+	err = SetupLogging(out, "info")
+	assert.Nil(t, err)
+	err = SetupSplitLogging(stdout, stderr, "invalid")
+	assert.NotNil(t, err)
+	logrus.Info("plain output")
+	assert.Contains(t, out.String(), "plain output")
+}
+
+func TestNewLogger(t *testing.T) {
+	err := SetupLogging(os.Stdout, "info")
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger, err := NewLogger(buf, "debug")
+	assert.Nil(t, err)
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+	assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+
+	logger.Debug("instance message")
+	assert.Contains(t, buf.String(), "instance message")
+
+	_, err = NewLogger(buf, "invalid")
+	assert.NotNil(t, err)
+}
+
+func TestSetupLoggingMulti(t *testing.T) {
+	defer logrus.SetOutput(os.Stdout)
+
+	first := &bytes.Buffer{}
+	second := &bytes.Buffer{}
+	err := SetupLoggingMulti("info", first, second)
+	assert.Nil(t, err)
+
+	logrus.Info("multi-writer test")
+	logrus.Debug("filtered")
+	assert.Contains(t, first.String(), "multi-writer test")
+	assert.Equal(t, first.String(), second.String())
+	assert.NotContains(t, first.String(), "filtered")
+
+	err = SetupLoggingMulti("unknown", first, second)
+	assert.NotNil(t, err)
+}
+
+func TestSetupFileLogging(t *testing.T) {
+	defer logrus.SetOutput(os.Stdout)
+
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	closer, err := SetupFileLogging(path, "debug", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "debug", logrus.GetLevel().String())
+
+	for i := 0; i < 5; i++ {
+		logrus.Debugf("line %d", i)
+	}
+
+	assert.Nil(t, closer.Close())
+
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "line 0")
+	assert.Contains(t, string(content), "line 4")
+
+	_, err = SetupFileLogging(filepath.Join(t.TempDir(), "invalid.log"), "unknown", 1)
+	assert.NotNil(t, err)
+}
+
 func TestAddVerbosityFlag(t *testing.T) {
 	cmd := &cobra.Command{}
 	AddVerbosityFlag(cmd)
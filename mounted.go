@@ -0,0 +1,77 @@
+package libstandard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadFromMountedDir applies the files of dir to the configuration structure, e.g. a mounted
+// Kubernetes Secret or ConfigMap. Each filename is matched against the env-tags of the fields
+// (including their env-prefix) and the file content is parsed as the value, with trailing
+// line-breaks removed. Fields without a matching file are left untouched, subdirectories and
+// the "..data" entries Kubernetes creates for atomic updates are skipped.
+//
+// Example:
+//
+//	 err := config.ReadFromMountedDir(&cfg, "/etc/secrets")
+//	 if err != nil {
+//	     ...
+//	 }
+func ReadFromMountedDir(cfg interface{}, dir string) error {
+	metaInfo, err := readStructMetadata(cfg)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "..") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		// Stat follows the symlinks which point into the "..data" directory
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		files[name] = strings.TrimRight(string(content), "\r\n")
+	}
+
+	for _, meta := range metaInfo {
+		for _, env := range meta.envList {
+			value, ok := files[env]
+			if !ok {
+				continue
+			}
+
+			if meta.presentTrue {
+				value = "true"
+			}
+
+			if err := parseValue(meta.fieldValue, value, meta.parseOptions); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	return nil
+}
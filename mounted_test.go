@@ -0,0 +1,70 @@
+package libstandard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFromMountedDir(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+
+	type config struct {
+		User      string   `env:"USER"`
+		Password  string   `env:"PASSWORD"`
+		Tags      []string `env:"TAGS"`
+		Untouched string   `env:"UNTOUCHED"`
+		Database  database `env-prefix:"DB_"`
+	}
+
+	// mimic the layout of a mounted Kubernetes Secret
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..2026_10_15_12_00_00.000000000")
+	assert.NoError(t, os.Mkdir(data, 0750))
+	assert.NoError(t, os.Symlink(filepath.Base(data), filepath.Join(dir, "..data")))
+
+	files := map[string]string{
+		"USER":     "admin",
+		"PASSWORD": "s3cr3t\n",
+		"TAGS":     "a,b",
+		"DB_HOST":  "db.local",
+		"DB_PORT":  "5432",
+		"UNKNOWN":  "ignored",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(data, name), []byte(content), 0600))
+		assert.NoError(t, os.Symlink(filepath.Join("..data", name), filepath.Join(dir, name)))
+	}
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0750))
+
+	cfg := config{Untouched: "keep"}
+	err := ReadFromMountedDir(&cfg, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		User:      "admin",
+		Password:  "s3cr3t",
+		Tags:      []string{"a", "b"},
+		Untouched: "keep",
+		Database:  database{Host: "db.local", Port: 5432},
+	}, cfg)
+}
+
+func TestReadFromMountedDirErrors(t *testing.T) {
+	type config struct {
+		Port int `env:"PORT"`
+	}
+
+	var cfg config
+	err := ReadFromMountedDir(&cfg, filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "PORT"), []byte("abc"), 0600))
+	err = ReadFromMountedDir(&cfg, dir)
+	assert.Error(t, err)
+}
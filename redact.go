@@ -0,0 +1,205 @@
+package libstandard
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// TagSecret marks a field as secret, its value is redacted by SafeString
+	TagSecret = "secret"
+
+	// RedactedValue replaces the values of secret fields
+	RedactedValue = "***"
+)
+
+// SafeString renders the structure as space-separated "field=value" pairs, suitable for logging.
+// Fields of nested structures are prefixed with the name of the parent field, e.g. "DB.Host=localhost",
+// structures in slices, arrays and maps additionally with their index or key, e.g. "Users[0].Name=admin".
+// Pointers and interfaces are followed to the values they hold, so structures behind them are expanded as well.
+// The values of fields tagged with `secret:"true"` are replaced by "***", the structure itself is not modified.
+//
+// Example:
+//
+//	 type Config struct {
+//	 	User     string `env:"USER"`
+//	 	Password string `env:"PASSWORD" secret:"true"`
+//	 }
+//
+//	 logrus.Debugf("Config: %s", config.SafeString(cfg))
+func SafeString(cfg interface{}) string {
+	pairs := make([]string, 0)
+	appendSafePairs(reflect.ValueOf(cfg), "", &pairs)
+	return strings.Join(pairs, " ")
+}
+
+// appendSafePairs appends the "field=value" pairs of the structure to pairs
+func appendSafePairs(s reflect.Value, prefix string, pairs *[]string) {
+	for s.Kind() == reflect.Ptr || s.Kind() == reflect.Interface {
+		if s.IsNil() {
+			return
+		}
+		s = s.Elem()
+	}
+
+	if s.Kind() != reflect.Struct {
+		return
+	}
+
+	typeInfo := s.Type()
+	for idx := 0; idx < s.NumField(); idx++ {
+		fType := typeInfo.Field(idx)
+		if !fType.IsExported() {
+			continue
+		}
+
+		name := prefix + fType.Name
+		if secret, _ := strconv.ParseBool(fType.Tag.Get(TagSecret)); secret {
+			*pairs = append(*pairs, name+"="+RedactedValue)
+			continue
+		}
+
+		appendSafeValue(s.Field(idx), name, pairs)
+	}
+}
+
+// appendSafeValue appends the "name=value" pair of the value to pairs. Structures, including the structure
+// elements of slices, arrays and maps, are expanded into the pairs of their fields, e.g. "Users[0].Name=a"
+func appendSafeValue(fld reflect.Value, name string, pairs *[]string) {
+	for (fld.Kind() == reflect.Ptr || fld.Kind() == reflect.Interface) && !fld.IsNil() {
+		fld = fld.Elem()
+	}
+
+	switch {
+	case fld.Kind() == reflect.Struct && !isFormattable(fld):
+		appendSafePairs(fld, name+".", pairs)
+
+	case (fld.Kind() == reflect.Slice || fld.Kind() == reflect.Array) && holdsStructs(fld):
+		for i := 0; i < fld.Len(); i++ {
+			appendSafeValue(fld.Index(i), fmt.Sprintf("%s[%d]", name, i), pairs)
+		}
+
+	case fld.Kind() == reflect.Map && holdsStructs(fld):
+		keys := fld.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		for _, key := range keys {
+			appendSafeValue(fld.MapIndex(key), fmt.Sprintf("%s[%v]", name, key.Interface()), pairs)
+		}
+
+	default:
+		*pairs = append(*pairs, fmt.Sprintf("%s=%v", name, fld.Interface()))
+	}
+}
+
+// holdsStructs reports whether the value is or contains structures which don't render themselves,
+// so they may contain secret fields
+func holdsStructs(v reflect.Value) bool {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return !isFormattable(v)
+
+	case reflect.Slice, reflect.Array:
+		if !mayHoldStructs(v.Type().Elem()) {
+			return false
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if holdsStructs(v.Index(i)) {
+				return true
+			}
+		}
+
+	case reflect.Map:
+		if !mayHoldStructs(v.Type().Elem()) {
+			return false
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			if holdsStructs(iter.Value()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// mayHoldStructs reports whether values of the type can hold structures which don't render themselves,
+// interfaces can hold any value and are therefore inspected by holdsStructs
+func mayHoldStructs(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return !reflect.PtrTo(t).Implements(stringerType) && !reflect.PtrTo(t).Implements(errorType)
+	case reflect.Interface:
+		return true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return mayHoldStructs(t.Elem())
+	}
+
+	return false
+}
+
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// isFormattable determines if the value renders itself, e.g. time.Time
+func isFormattable(v reflect.Value) bool {
+	switch v.Interface().(type) {
+	case fmt.Stringer, error:
+		return true
+	}
+
+	if v.CanAddr() {
+		switch v.Addr().Interface().(type) {
+		case fmt.Stringer, error:
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactMap returns a copy of the map where the values of all keys containing one of the secretKeys
+// are replaced by "***". Keys are matched case-insensitively, e.g. "password" matches "DB_PASSWORD".
+// The given map is not modified.
+func RedactMap(m map[string]string, secretKeys ...string) map[string]string {
+	redacted := make(map[string]string, len(m))
+	for key, value := range m {
+		if isSecretKey(key, secretKeys) {
+			value = RedactedValue
+		}
+
+		redacted[key] = value
+	}
+
+	return redacted
+}
+
+// isSecretKey determines if the key contains one of the secretKeys, ignoring case
+func isSecretKey(key string, secretKeys []string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if secret != "" && strings.Contains(key, strings.ToLower(secret)) {
+			return true
+		}
+	}
+
+	return false
+}
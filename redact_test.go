@@ -0,0 +1,162 @@
+package libstandard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeString(t *testing.T) {
+	type credentials struct {
+		User     string
+		Password string `secret:"true"`
+	}
+
+	type database struct {
+		Host        string
+		Credentials credentials
+		Replicas    *credentials
+	}
+
+	type config struct {
+		Name     string
+		Tokens   []string `secret:"true"`
+		Hosts    []string
+		Port     int
+		Timeout  time.Duration
+		Database database
+		Internal bool `secret:"false"`
+		hidden   string
+	}
+
+	cfg := config{
+		Name:    "app",
+		Tokens:  []string{"t1", "t2"},
+		Hosts:   []string{"a", "b"},
+		Port:    8080,
+		Timeout: 5 * time.Second,
+		Database: database{
+			Host:        "localhost",
+			Credentials: credentials{User: "admin", Password: "s3cret"},
+			Replicas:    &credentials{User: "reader", Password: "r3ad"},
+		},
+		hidden: "hidden",
+	}
+
+	out := SafeString(&cfg)
+	assert.Equal(t, "Name=app Tokens=*** Hosts=[a b] Port=8080 Timeout=5s Database.Host=localhost "+
+		"Database.Credentials.User=admin Database.Credentials.Password=*** "+
+		"Database.Replicas.User=reader Database.Replicas.Password=*** Internal=false", out)
+	assert.Equal(t, out, SafeString(cfg))
+	assert.Equal(t, "s3cret", cfg.Database.Credentials.Password)
+	assert.Equal(t, []string{"t1", "t2"}, cfg.Tokens)
+
+	cfg.Database.Replicas = nil
+	assert.Contains(t, SafeString(cfg), "Database.Replicas=<nil> ")
+
+	assert.Equal(t, "", SafeString(nil))
+	assert.Equal(t, "", SafeString(42))
+}
+
+func TestSafeStringWithStructElements(t *testing.T) {
+	type credentials struct {
+		User     string
+		Password string `secret:"true"`
+	}
+
+	type config struct {
+		Users    []credentials
+		Pointers []*credentials
+		Fixed    [1]credentials
+		ByName   map[string]credentials
+		Empty    []credentials
+		Times    []time.Duration
+	}
+
+	cfg := config{
+		Users:    []credentials{{User: "a", Password: "pa"}, {User: "b", Password: "pb"}},
+		Pointers: []*credentials{{User: "c", Password: "pc"}, nil},
+		Fixed:    [1]credentials{{User: "d", Password: "pd"}},
+		ByName:   map[string]credentials{"y": {User: "y", Password: "py"}, "x": {User: "x", Password: "px"}},
+		Times:    []time.Duration{time.Second},
+	}
+
+	out := SafeString(cfg)
+	assert.Equal(t, "Users[0].User=a Users[0].Password=*** Users[1].User=b Users[1].Password=*** "+
+		"Pointers[0].User=c Pointers[0].Password=*** Pointers[1]=<nil> Fixed[0].User=d Fixed[0].Password=*** "+
+		"ByName[x].User=x ByName[x].Password=*** ByName[y].User=y ByName[y].Password=*** Empty=[] Times=[1s]", out)
+	assert.NotContains(t, out, "pa")
+}
+
+func TestSafeStringWithInterfaces(t *testing.T) {
+	type credentials struct {
+		User     string
+		Password string `secret:"true"`
+	}
+
+	type config struct {
+		Plugin  interface{}
+		Pointer interface{}
+		Nil     interface{}
+		Values  []interface{}
+		Options map[string]interface{}
+		Nested  [][]credentials
+	}
+
+	cfg := config{
+		Plugin:  credentials{User: "u", Password: "hunter2"},
+		Pointer: &credentials{User: "p", Password: "hunter2"},
+		Values:  []interface{}{"a", &credentials{User: "v", Password: "hunter2"}},
+		Options: map[string]interface{}{"timeout": 5, "auth": credentials{User: "o", Password: "hunter2"}},
+		Nested:  [][]credentials{{{User: "n", Password: "hunter2"}}},
+	}
+
+	out := SafeString(cfg)
+	assert.Equal(t, "Plugin.User=u Plugin.Password=*** Pointer.User=p Pointer.Password=*** Nil=<nil> "+
+		"Values[0]=a Values[1].User=v Values[1].Password=*** Options[auth].User=o Options[auth].Password=*** "+
+		"Options[timeout]=5 Nested[0][0].User=n Nested[0][0].Password=***", out)
+	assert.NotContains(t, out, "hunter2")
+
+	cfg = config{Values: []interface{}{"a", 1}, Options: map[string]interface{}{"timeout": 5}}
+	assert.Contains(t, SafeString(cfg), "Values=[a 1] Options=map[timeout:5] ")
+}
+
+func TestRedactMap(t *testing.T) {
+	m := map[string]string{
+		"user":        "admin",
+		"password":    "secret",
+		"DB_PASSWORD": "secret",
+		"ApiToken":    "abc",
+		"host":        "localhost",
+	}
+
+	tests := []struct {
+		name       string
+		secretKeys []string
+		want       map[string]string
+	}{
+		{
+			name:       "exact",
+			secretKeys: []string{"password"},
+			want:       map[string]string{"user": "admin", "password": "***", "DB_PASSWORD": "***", "ApiToken": "abc", "host": "localhost"},
+		},
+		{
+			name:       "substring",
+			secretKeys: []string{"TOKEN", "pass"},
+			want:       map[string]string{"user": "admin", "password": "***", "DB_PASSWORD": "***", "ApiToken": "***", "host": "localhost"},
+		},
+		{
+			name: "none",
+			want: m,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedactMap(m, tt.secretKeys...))
+		})
+	}
+
+	assert.Equal(t, "secret", m["password"])
+}
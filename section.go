@@ -0,0 +1,130 @@
+package libstandard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadSection works like Read, but only decodes the value under section of the config-file into cfg,
+// e.g. to read the own part of a config-file shared by several services. Nested sections are addressed
+// with a dotted path like "services.api". Environment variables and flags are applied as usual.
+// Sections are supported for yaml and json files, a missing section is an error.
+//
+// Example:
+//
+//	 err := config.ReadSection(&cfg, cmd.Flags(), "config.yml", "services.api", DefaultFileConfig{})
+//	 if err != nil {
+//	     ...
+//	 }
+func ReadSection(cfg interface{}, flags *pflag.FlagSet, file, section string, defaultCfg DefaultFileConfig) error {
+	return read(cfg, flags, file, defaultCfg, ReadOptions{Section: section}, nil)
+}
+
+// sectionParser returns a parser which decodes only the section of the file with the given extension
+func sectionParser(ext string, opts ReadOptions) (ParserFunc, error) {
+	path := strings.Split(opts.Section, ".")
+
+	switch ext {
+	case ".yaml", ".yml":
+		return func(r io.Reader, str interface{}) error {
+			return parseYAMLSection(r, str, path, opts.MergeYAMLDocuments)
+		}, nil
+	case ".json":
+		return func(r io.Reader, str interface{}) error {
+			return parseJSONSection(r, str, path)
+		}, nil
+	default:
+		return nil, fmt.Errorf("sections are not supported for file format '%s'", ext)
+	}
+}
+
+// parseYAMLSection decodes the section of the first or, if merge is set, of all YAML-documents into the data structure
+func parseYAMLSection(r io.Reader, str interface{}, path []string, merge bool) error {
+	decoder := yaml.NewDecoder(r)
+	found := false
+
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if node := lookupYAMLSection(&doc, path); node != nil {
+			if err := decodeYAMLMerged(str, node.Decode); err != nil {
+				return err
+			}
+			found = true
+		}
+
+		if !merge {
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("section %q not found", strings.Join(path, "."))
+	}
+
+	return nil
+}
+
+// lookupYAMLSection walks the mappings of the document along the path and returns the node found or nil
+func lookupYAMLSection(node *yaml.Node, path []string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+
+	return node
+}
+
+// parseJSONSection decodes the section of the JSON-object into the data structure
+func parseJSONSection(r io.Reader, str interface{}, path []string) error {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return err
+	}
+
+	for i, key := range path {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return fmt.Errorf("section %q is not an object: %w", strings.Join(path[:i], "."), err)
+		}
+
+		value, ok := object[key]
+		if !ok {
+			return fmt.Errorf("section %q not found", strings.Join(path, "."))
+		}
+		raw = value
+	}
+
+	return json.Unmarshal(raw, str)
+}
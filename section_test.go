@@ -0,0 +1,93 @@
+package libstandard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSection(t *testing.T) {
+	type config struct {
+		Host  string `yaml:"host" json:"host" env:"HOST"`
+		Port  int    `yaml:"port" json:"port" env:"PORT"`
+		Debug bool   `yaml:"debug" json:"debug" env:"DEBUG" env-default:"true"`
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.yaml": "services:\n  api:\n    host: api.local\n    port: 8080\n  worker:\n    host: worker.local\n",
+		"config.json": `{"services": {"api": {"host": "api.local", "port": 8080}, "worker": {"host": "worker.local"}}}`,
+		"config.ini":  "[api]\nhost = api.local\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		section string
+		env     map[string]string
+		want    config
+		wantErr string
+	}{
+		{
+			name:    "yaml nested section",
+			file:    "config.yaml",
+			section: "services.api",
+			want:    config{Host: "api.local", Port: 8080, Debug: true},
+		},
+		{
+			name:    "json nested section",
+			file:    "config.json",
+			section: "services.worker",
+			want:    config{Host: "worker.local", Debug: true},
+		},
+		{
+			name:    "env overrides section",
+			file:    "config.yaml",
+			section: "services.api",
+			env:     map[string]string{"PORT": "9090"},
+			want:    config{Host: "api.local", Port: 9090, Debug: true},
+		},
+		{
+			name:    "yaml section absent",
+			file:    "config.yaml",
+			section: "services.db",
+			wantErr: `config file parsing error: section "services.db" not found`,
+		},
+		{
+			name:    "json section absent",
+			file:    "config.json",
+			section: "services.api.tls",
+			wantErr: `config file parsing error: section "services.api.tls" not found`,
+		},
+		{
+			name:    "unsupported format",
+			file:    "config.ini",
+			section: "api",
+			wantErr: "sections are not supported for file format '.ini'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := ReadSection(&cfg, nil, filepath.Join(dir, tt.file), tt.section, DefaultFileConfig{})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
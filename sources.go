@@ -0,0 +1,126 @@
+package libstandard
+
+import (
+	"reflect"
+
+	"github.com/spf13/pflag"
+)
+
+// Source describes where the value of a config-field came from
+type Source int
+
+const (
+	// SourceNone means that the field was not written
+	SourceNone Source = iota
+	// SourceDefault means that the value came from the env-default tag or the default of a flag
+	SourceDefault
+	// SourceFile means that the value came from the config-file
+	SourceFile
+	// SourceEnv means that the value came from an environment variable
+	SourceEnv
+	// SourceFlag means that the value came from an explicitly set cmd-flag
+	SourceFlag
+)
+
+// String returns the name of the source
+func (s Source) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	default:
+		return "none"
+	}
+}
+
+// ReadWithSources works like Read, but additionally returns which source last wrote each field.
+// The map is keyed by the field name, fields of nested structures are prefixed with the names
+// of their parent fields, e.g. "Database.Host". Fields which were not written are reported as SourceNone.
+// Fields read from the file are detected by comparing their values before and after parsing the file,
+// so a value in the file equal to the previous value is not reported as SourceFile.
+//
+// Example:
+//
+//	 sources, err := config.ReadWithSources(&cfg, cmd.Flags(), "config.yml", DefaultFileConfig{})
+//	 if err != nil {
+//	     ...
+//	 }
+//	 logrus.Debugf("Port was set by %s", sources["Port"])
+func ReadWithSources(cfg interface{}, flags *pflag.FlagSet, file string, defaultCfg DefaultFileConfig) (map[string]Source, error) {
+	sources := make(map[string]Source)
+	err := read(cfg, flags, file, defaultCfg, ReadOptions{}, sources)
+	return sources, err
+}
+
+// recordSource stores the source of the field, if sources are tracked
+func recordSource(sources map[string]Source, meta structMeta, source Source) {
+	if sources != nil {
+		sources[meta.fieldPath] = source
+	}
+}
+
+// snapshotFields copies the current values of all fields
+func snapshotFields(metaInfo []structMeta) []interface{} {
+	snapshot := make([]interface{}, len(metaInfo))
+	for i, meta := range metaInfo {
+		snapshot[i] = deepCopy(meta.fieldValue).Interface()
+	}
+	return snapshot
+}
+
+// recordChangedFields stores the source of all fields whose values differ from the snapshot
+func recordChangedFields(sources map[string]Source, metaInfo []structMeta, snapshot []interface{}, source Source) {
+	for i, meta := range metaInfo {
+		if !reflect.DeepEqual(snapshot[i], meta.fieldValue.Interface()) {
+			recordSource(sources, meta, source)
+		}
+	}
+}
+
+// deepCopy copies the value including the content of slices, maps and pointers
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
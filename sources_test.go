@@ -0,0 +1,60 @@
+package libstandard
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWithSources(t *testing.T) {
+	type database struct {
+		Host string `yaml:"host" env:"TEST_DB_HOST"`
+	}
+
+	type config struct {
+		Port     int      `yaml:"port" flag:"port"`
+		Name     string   `yaml:"name" env:"TEST_NAME"`
+		Tags     []string `yaml:"tags"`
+		Timeout  int      `env:"TEST_TIMEOUT" env-default:"30"`
+		Level    string   `flag:"level"`
+		Unset    string   `env:"TEST_UNSET"`
+		Database database `yaml:"database"`
+	}
+
+	tmpFile, err := os.CreateTemp(os.TempDir(), "*.yaml")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString("port: 8080\nname: file\ntags: [a, b]\ndatabase:\n  host: file.host\n")
+	assert.NoError(t, err)
+
+	os.Setenv("TEST_NAME", "env")
+	defer os.Clearenv()
+
+	flagSet := &pflag.FlagSet{}
+	flagSet.Int("port", 0, "")
+	flagSet.String("level", "info", "")
+	assert.NoError(t, flagSet.Set("port", "9090"))
+
+	var cfg config
+	sources, err := ReadWithSources(&cfg, flagSet, tmpFile.Name(), DefaultFileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Port: 9090, Name: "env", Tags: []string{"a", "b"}, Timeout: 30, Level: "info", Database: database{Host: "file.host"}}, cfg)
+
+	assert.Equal(t, SourceFlag, sources["Port"])
+	assert.Equal(t, SourceEnv, sources["Name"])
+	assert.Equal(t, SourceFile, sources["Tags"])
+	assert.Equal(t, SourceDefault, sources["Timeout"])
+	assert.Equal(t, SourceDefault, sources["Level"])
+	assert.Equal(t, SourceNone, sources["Unset"])
+	assert.Equal(t, SourceFile, sources["Database.Host"])
+	assert.Equal(t, "file", sources["Database.Host"].String())
+
+	sources, err = ReadWithSources(42, nil, "", DefaultFileConfig{})
+	assert.Error(t, err)
+	assert.Empty(t, sources)
+}
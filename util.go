@@ -1,12 +1,42 @@
 package libstandard
 
-import "strings"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
 
-// Unescape removes backslashes and double-quotes from strings
+// Unescape removes backslashes and double-quotes from strings.
+// It works in a single pass and doesn't allocate if s contains none of them.
 func Unescape(s string) string {
-	s = strings.ReplaceAll(s, "\\", "")
-	s = strings.ReplaceAll(s, "\"", "")
-	return s
+	idx := strings.IndexAny(s, "\\\"")
+	if idx == -1 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) - 1)
+	b.WriteString(s[:idx])
+
+	for i := idx + 1; i < len(s); i++ {
+		if c := s[i]; c != '\\' && c != '"' {
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// Escape wraps the string in double-quotes and escapes embedded backslashes and double-quotes with a backslash.
+// Note that Escape is not the exact inverse of Unescape: Unescape drops all backslashes and double-quotes,
+// so Unescape(Escape(s)) only equals s if s contains none of them.
+func Escape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	return "\"" + s + "\""
 }
 
 // Unique removes all duplicate values from the given slice
@@ -31,8 +61,33 @@ func FirstOrEmpty(slice []string) string {
 	return ""
 }
 
-// ToMap converts a string-slice to a map[string]string
+// LastOrEmpty returns the last string from the slice.
+func LastOrEmpty(slice []string) string {
+	if len(slice) > 0 {
+		return slice[len(slice)-1]
+	}
+
+	return ""
+}
+
+// Last returns the last element of the slice and true, or the zero value and false if the slice is empty.
+func Last[T any](slice []T) (T, bool) {
+	if len(slice) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return slice[len(slice)-1], true
+}
+
+// ToMap converts a string-slice of "key=value" entries to a map[string]string
 func ToMap(slice []string) map[string]string {
+	return ToMapSep(slice, "=")
+}
+
+// ToMapSep converts a string-slice to a map[string]string, splitting each entry at the first occurrence of sep.
+// Entries without the separator are added with an empty value.
+func ToMapSep(slice []string, sep string) map[string]string {
 	m := make(map[string]string, 0)
 
 	for _, s := range slice {
@@ -40,9 +95,320 @@ func ToMap(slice []string) map[string]string {
 			continue
 		}
 
-		splitted := strings.Split(s, "=")
-		m[splitted[0]] = splitted[1]
+		splitted := strings.SplitN(s, sep, 2)
+		if len(splitted) == 1 {
+			m[splitted[0]] = ""
+		} else {
+			m[splitted[0]] = splitted[1]
+		}
 	}
 
 	return m
 }
+
+// MapToSlice converts a map[string]string to a string-slice of "key=value" entries, sorted by key.
+// It is the inverse of ToMap.
+func MapToSlice(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	slice := make([]string, 0, len(m))
+	for _, k := range keys {
+		slice = append(slice, k+"="+m[k])
+	}
+
+	return slice
+}
+
+// Filter returns all elements of the slice for which keep returns true, preserving their order.
+func Filter[T any](in []T, keep func(T) bool) []T {
+	out := []T{}
+	for _, v := range in {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// Partition splits the slice into the elements for which pred returns true and the rest in a single pass,
+// preserving their order within both halves.
+func Partition[T any](in []T, pred func(T) bool) (matched, rest []T) {
+	matched, rest = []T{}, []T{}
+	for _, v := range in {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+
+	return matched, rest
+}
+
+// Map applies f to every element of the slice and returns the results in the same order.
+func Map[T, U any](in []T, f func(T) U) []U {
+	out := make([]U, 0, len(in))
+	for _, v := range in {
+		out = append(out, f(v))
+	}
+
+	return out
+}
+
+// ForEach calls fn for each element of the slice in order, e.g. to log each entry.
+func ForEach[T any](in []T, fn func(T)) {
+	for _, v := range in {
+		fn(v)
+	}
+}
+
+// Flatten concatenates the inner slices into a single slice, preserving their order.
+func Flatten[T any](in [][]T) []T {
+	size := 0
+	for _, inner := range in {
+		size += len(inner)
+	}
+
+	out := make([]T, 0, size)
+	for _, inner := range in {
+		out = append(out, inner...)
+	}
+
+	return out
+}
+
+// Chunk splits the slice into consecutive batches of at most size elements, the last batch holds the remainder.
+// A size <= 0 returns the whole input as a single batch. An empty input returns no batches.
+func Chunk[T any](in []T, size int) [][]T {
+	chunks := [][]T{}
+	if len(in) == 0 {
+		return chunks
+	}
+
+	if size <= 0 {
+		return append(chunks, in)
+	}
+
+	for size < len(in) {
+		chunks = append(chunks, in[:size:size])
+		in = in[size:]
+	}
+
+	return append(chunks, in)
+}
+
+// IndexOf returns the index of the first occurrence of value in the slice, or -1 if it is not present.
+func IndexOf[T comparable](slice []T, value T) int {
+	for i, v := range slice {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// SliceDiff returns the elements of new which are not in old and the elements of old which are not in new,
+// each in the order of its input slice.
+func SliceDiff[T comparable](old, new []T) (added, removed []T) {
+	oldSet := make(map[T]struct{}, len(old))
+	for _, v := range old {
+		oldSet[v] = struct{}{}
+	}
+
+	newSet := make(map[T]struct{}, len(new))
+	for _, v := range new {
+		newSet[v] = struct{}{}
+	}
+
+	added = Filter(new, func(v T) bool {
+		_, ok := oldSet[v]
+		return !ok
+	})
+	removed = Filter(old, func(v T) bool {
+		_, ok := newSet[v]
+		return !ok
+	})
+
+	return added, removed
+}
+
+// Coalesce returns the first value which is not the zero value of its type, or the zero value if all are zero.
+// Values are compared with == against the zero value, so it only works for comparable types.
+func Coalesce[T comparable](values ...T) T {
+	var zero T
+	for _, v := range values {
+		if v != zero {
+			return v
+		}
+	}
+
+	return zero
+}
+
+// If returns a if cond is true, otherwise b.
+// Unlike a real ternary operator both arguments are evaluated before the call, so avoid passing expressions with side effects.
+func If[T any](cond bool, a, b T) T {
+	if cond {
+		return a
+	}
+
+	return b
+}
+
+// GroupBy groups the elements of the slice by the key returned by keyFn, preserving their order within each group.
+func GroupBy[T any, K comparable](in []T, keyFn func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, v := range in {
+		key := keyFn(v)
+		groups[key] = append(groups[key], v)
+	}
+
+	return groups
+}
+
+// DeepMerge merges src into dst recursively and returns the result as a new map, leaving both inputs unchanged.
+// Nested maps are merged key by key, for all other values src wins, also if only one side is a map.
+// Slices are not merged but replaced wholesale by the value of src.
+func DeepMerge(dst, src map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(dst)+len(src))
+	for k, v := range dst {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = DeepMerge(m, nil)
+		}
+		out[k] = v
+	}
+
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := out[k].(map[string]interface{})
+
+		switch {
+		case srcIsMap && dstIsMap:
+			out[k] = DeepMerge(dstMap, srcMap)
+		case srcIsMap:
+			out[k] = DeepMerge(srcMap, nil)
+		default:
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// Retry calls fn until it succeeds, at most attempts times, e.g. to fetch configuration from a remote source.
+// It waits backoff after the first failure and doubles the wait after each further failure.
+// If all attempts fail, the error of the last attempt is returned. Less than one attempt is an error, fn isn't called then.
+func Retry(attempts int, backoff time.Duration, fn func() error) error {
+	return RetryContext(context.Background(), attempts, backoff, fn)
+}
+
+// RetryContext is like Retry, but stops waiting and returns the context's error together with the error
+// of the last attempt as soon as ctx is done.
+func RetryContext(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+	if attempts < 1 {
+		return fmt.Errorf("invalid number of attempts %d, at least one is required", attempts)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt >= attempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w, last error: %v", ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
+
+// Debounce returns a function which invokes fn once d has passed since it was called the last time,
+// so a burst of calls results in a single call of fn. fn is invoked on its own goroutine.
+func Debounce(d time.Duration, fn func()) func() {
+	var mu sync.Mutex
+	var timer *time.Timer
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(d, fn)
+	}
+}
+
+// Set is a set of comparable values which preserves the order of insertion. The zero value is an empty set.
+type Set[T comparable] struct {
+	index map[T]int
+	items []T
+}
+
+// NewSet returns a set containing the given values.
+func NewSet[T comparable](values ...T) *Set[T] {
+	s := &Set[T]{}
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return s
+}
+
+// Add adds the value to the set and reports whether it was not present before.
+func (s *Set[T]) Add(value T) bool {
+	if s.Has(value) {
+		return false
+	}
+
+	if s.index == nil {
+		s.index = make(map[T]int)
+	}
+
+	s.index[value] = len(s.items)
+	s.items = append(s.items, value)
+	return true
+}
+
+// Has reports whether the value is in the set.
+func (s *Set[T]) Has(value T) bool {
+	_, ok := s.index[value]
+	return ok
+}
+
+// Remove removes the value from the set and reports whether it was present.
+func (s *Set[T]) Remove(value T) bool {
+	idx, ok := s.index[value]
+	if !ok {
+		return false
+	}
+
+	delete(s.index, value)
+	s.items = append(s.items[:idx], s.items[idx+1:]...)
+	for i := idx; i < len(s.items); i++ {
+		s.index[s.items[i]] = i
+	}
+
+	return true
+}
+
+// Len returns the number of values in the set.
+func (s *Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Slice returns a copy of the values in the order they were added.
+func (s *Set[T]) Slice() []T {
+	return append([]T{}, s.items...)
+}
@@ -1,7 +1,14 @@
 package libstandard
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -58,6 +65,69 @@ func TestUnescape(t *testing.T) {
 	}
 }
 
+func TestUnescapeMatchesReplaceAll(t *testing.T) {
+	inputs := []string{
+		"This is a test",
+		"",
+		"This is \"a\" test",
+		"This \\is a test",
+		"This is \\\"a\"\\ test",
+		"\\",
+		"\"\"\\\\",
+		"trailing\\",
+		"ünïcödé \"ß\"",
+	}
+
+	for _, input := range inputs {
+		expected := strings.ReplaceAll(strings.ReplaceAll(input, "\\", ""), "\"", "")
+		assert.Equal(t, expected, Unescape(input), input)
+	}
+}
+
+func BenchmarkUnescape(b *testing.B) {
+	inputs := []string{"plain value", "\"quoted\" value", "back\\slashed \\\"value\""}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		for _, input := range inputs {
+			_ = Unescape(input)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []stringTestData{
+		{
+			input:    "This is a test",
+			expected: "\"This is a test\"",
+		},
+		{
+			input:    "",
+			expected: "\"\"",
+		},
+		{
+			input:    "This is \"a\" test",
+			expected: "\"This is \\\"a\\\" test\"",
+		},
+		{
+			input:    "This \\is a test",
+			expected: "\"This \\\\is a test\"",
+		},
+		{
+			input:    "This is \\\"a",
+			expected: "\"This is \\\\\\\"a\"",
+		},
+	}
+
+	for _, v := range tests {
+		t.Run("", func(t *testing.T) {
+			out := Escape(v.input)
+			assert.Equal(t, v.expected, out)
+			assert.Equal(t, Unescape(v.input), Unescape(out))
+		})
+	}
+}
+
 func TestUnique(t *testing.T) {
 	tests := []sliceTestData{
 		{
@@ -114,6 +184,52 @@ func TestFirstOrEmpty(t *testing.T) {
 	}
 }
 
+func TestLastOrEmpty(t *testing.T) {
+	tests := []sliceStringTestData{
+		{
+			input:    []string{},
+			expected: "",
+		},
+		{
+			input:    []string{"a"},
+			expected: "a",
+		},
+		{
+			input:    []string{"b", "a"},
+			expected: "a",
+		},
+		{
+			input:    []string{"c", "", ""},
+			expected: "",
+		},
+	}
+
+	for _, v := range tests {
+		t.Run("", func(t *testing.T) {
+			out := LastOrEmpty(v.input)
+			assert.Equal(t, v.expected, out)
+		})
+	}
+}
+
+func TestLast(t *testing.T) {
+	v, ok := Last([]int{1, 2, 3})
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	v, ok = Last([]int{7})
+	assert.True(t, ok)
+	assert.Equal(t, 7, v)
+
+	v, ok = Last([]int{})
+	assert.False(t, ok)
+	assert.Equal(t, 0, v)
+
+	s, ok := Last[string](nil)
+	assert.False(t, ok)
+	assert.Equal(t, "", s)
+}
+
 func TestToMap(t *testing.T) {
 	tests := []mapSliceStringTestData{
 		{
@@ -141,3 +257,302 @@ func TestToMap(t *testing.T) {
 		})
 	}
 }
+
+func TestToMapSep(t *testing.T) {
+	assert.Equal(t, map[string]string{}, ToMapSep([]string{"", " "}, ":"))
+	assert.Equal(t, map[string]string{"a": "b", "b": "c"}, ToMapSep([]string{"a:b", "b:c"}, ":"))
+	assert.Equal(t, map[string]string{"url": "http://example.com:8080"}, ToMapSep([]string{"url:http://example.com:8080"}, ":"))
+	assert.Equal(t, map[string]string{"a": "b=c"}, ToMapSep([]string{"a=b=c"}, "="))
+	assert.Equal(t, map[string]string{"a": "b"}, ToMapSep([]string{"a=>b"}, "=>"))
+	assert.Equal(t, map[string]string{"a": ""}, ToMapSep([]string{"a"}, ":"))
+	assert.Equal(t, map[string]string{"a": "b=c"}, ToMap([]string{"a=b=c"}))
+}
+
+func TestMapToSlice(t *testing.T) {
+	assert.Equal(t, []string{}, MapToSlice(nil))
+	assert.Equal(t, []string{}, MapToSlice(map[string]string{}))
+	assert.Equal(t, []string{"a=x", "b=", "c=z"}, MapToSlice(map[string]string{"c": "z", "a": "x", "b": ""}))
+
+	m := map[string]string{"PATH": "/bin", "HOME": "/root", "EMPTY": ""}
+	assert.Equal(t, m, ToMap(MapToSlice(m)))
+}
+
+func TestFilter(t *testing.T) {
+	isEven := func(i int) bool { return i%2 == 0 }
+
+	assert.Equal(t, []int{}, Filter(nil, isEven))
+	assert.Equal(t, []int{}, Filter([]int{}, isEven))
+	assert.Equal(t, []int{}, Filter([]int{1, 3, 5}, isEven))
+	assert.Equal(t, []int{4, 2, 6}, Filter([]int{1, 4, 3, 2, 6}, isEven))
+	assert.Equal(t, []string{"a", "c"}, Filter([]string{"a", "", "c"}, func(s string) bool { return s != "" }))
+}
+
+func TestPartition(t *testing.T) {
+	isEven := func(i int) bool { return i%2 == 0 }
+
+	matched, rest := Partition(nil, isEven)
+	assert.Equal(t, []int{}, matched)
+	assert.Equal(t, []int{}, rest)
+
+	matched, rest = Partition([]int{2, 4, 6}, isEven)
+	assert.Equal(t, []int{2, 4, 6}, matched)
+	assert.Equal(t, []int{}, rest)
+
+	matched, rest = Partition([]int{1, 3, 5}, isEven)
+	assert.Equal(t, []int{}, matched)
+	assert.Equal(t, []int{1, 3, 5}, rest)
+
+	matched, rest = Partition([]int{1, 4, 3, 2, 6, 5}, isEven)
+	assert.Equal(t, []int{4, 2, 6}, matched)
+	assert.Equal(t, []int{1, 3, 5}, rest)
+}
+
+func TestMap(t *testing.T) {
+	assert.Equal(t, []string{}, Map(nil, strconv.Itoa))
+	assert.Equal(t, []string{}, Map([]int{}, strconv.Itoa))
+	assert.Equal(t, []string{"3", "1", "2"}, Map([]int{3, 1, 2}, strconv.Itoa))
+	assert.Equal(t, []int{1, 0, 3}, Map([]string{"a", "", "abc"}, func(s string) int { return len(s) }))
+}
+
+func TestForEach(t *testing.T) {
+	visited := []string{}
+	ForEach([]string{"b", "a", "c", "a"}, func(s string) {
+		visited = append(visited, s)
+	})
+	assert.Equal(t, []string{"b", "a", "c", "a"}, visited)
+
+	calls := 0
+	ForEach[int](nil, func(int) { calls++ })
+	assert.Equal(t, 0, calls)
+}
+
+func TestFlatten(t *testing.T) {
+	assert.Equal(t, []string{}, Flatten[string](nil))
+	assert.Equal(t, []string{}, Flatten([][]string{}))
+	assert.Equal(t, []string{}, Flatten([][]string{{}, nil}))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, Flatten([][]string{{"a", "b"}, {}, {"c"}, nil, {"d"}}))
+	assert.Equal(t, []int{3, 1, 2}, Flatten([][]int{{3}, {1, 2}}))
+}
+
+func TestChunk(t *testing.T) {
+	assert.Equal(t, [][]int{}, Chunk([]int{}, 2))
+	assert.Equal(t, [][]int{}, Chunk[int](nil, 2))
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, Chunk([]int{1, 2, 3, 4}, 2))
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, Chunk([]int{1, 2, 3, 4, 5}, 2))
+	assert.Equal(t, [][]int{{1, 2, 3}}, Chunk([]int{1, 2, 3}, 10))
+	assert.Equal(t, [][]int{{1, 2, 3}}, Chunk([]int{1, 2, 3}, 0))
+	assert.Equal(t, [][]int{{1, 2, 3}}, Chunk([]int{1, 2, 3}, -1))
+
+	chunks := Chunk([]int{1, 2, 3}, 2)
+	chunks[0] = append(chunks[0], 42)
+	assert.Equal(t, []int{3}, chunks[1])
+}
+
+func TestIndexOf(t *testing.T) {
+	assert.Equal(t, 0, IndexOf([]string{"a", "b", "a"}, "a"))
+	assert.Equal(t, 1, IndexOf([]string{"a", "b", "c"}, "b"))
+	assert.Equal(t, 2, IndexOf([]int{1, 2, 3}, 3))
+	assert.Equal(t, -1, IndexOf([]string{"a", "b", "c"}, "d"))
+	assert.Equal(t, -1, IndexOf([]string{}, ""))
+	assert.Equal(t, -1, IndexOf(nil, "a"))
+}
+
+func TestSliceDiff(t *testing.T) {
+	added, removed := SliceDiff([]string{"a", "b"}, []string{"a", "c", "b", "d"})
+	assert.Equal(t, []string{"c", "d"}, added)
+	assert.Equal(t, []string{}, removed)
+
+	added, removed = SliceDiff([]string{"c", "a", "b", "d"}, []string{"a", "b"})
+	assert.Equal(t, []string{}, added)
+	assert.Equal(t, []string{"c", "d"}, removed)
+
+	addedInts, removedInts := SliceDiff([]int{1, 2, 3}, []int{5, 3, 4, 1})
+	assert.Equal(t, []int{5, 4}, addedInts)
+	assert.Equal(t, []int{2}, removedInts)
+
+	addedInts, removedInts = SliceDiff[int](nil, nil)
+	assert.Equal(t, []int{}, addedInts)
+	assert.Equal(t, []int{}, removedInts)
+}
+
+func TestCoalesce(t *testing.T) {
+	assert.Equal(t, "b", Coalesce("", "b", "c"))
+	assert.Equal(t, "a", Coalesce("a", "b"))
+	assert.Equal(t, "", Coalesce("", ""))
+	assert.Equal(t, "", Coalesce[string]())
+	assert.Equal(t, 3, Coalesce(0, 0, 3))
+	assert.Equal(t, -1, Coalesce(-1, 2))
+	assert.Equal(t, 0, Coalesce(0, 0))
+}
+
+func TestIf(t *testing.T) {
+	assert.Equal(t, "a", If(true, "a", "b"))
+	assert.Equal(t, "b", If(false, "a", "b"))
+	assert.Equal(t, 1, If(true, 1, 2))
+	assert.Equal(t, 2, If(false, 1, 2))
+
+	a, b := 1, 2
+	assert.Same(t, &a, If(true, &a, &b))
+	assert.Same(t, &b, If(false, &a, &b))
+	assert.Nil(t, If[*int](false, &a, nil))
+}
+
+func TestGroupBy(t *testing.T) {
+	firstLetter := func(s string) string { return s[:1] }
+
+	assert.Equal(t, map[string][]string{}, GroupBy([]string{}, firstLetter))
+	assert.Equal(t, map[string][]string{}, GroupBy(nil, firstLetter))
+	assert.Equal(t, map[string][]string{
+		"a": {"apple", "avocado", "apricot"},
+		"b": {"banana", "blueberry"},
+		"c": {"cherry"},
+	}, GroupBy([]string{"apple", "banana", "avocado", "cherry", "blueberry", "apricot"}, firstLetter))
+	assert.Equal(t, map[bool][]int{true: {2, 4}, false: {1, 3}}, GroupBy([]int{1, 2, 3, 4}, func(i int) bool { return i%2 == 0 }))
+}
+
+func TestDeepMerge(t *testing.T) {
+	dst := map[string]interface{}{
+		"name": "base",
+		"tags": []interface{}{"a", "b"},
+		"database": map[string]interface{}{
+			"host": "localhost",
+			"port": 5432,
+			"pool": map[string]interface{}{"min": 1, "max": 10},
+		},
+		"logging": map[string]interface{}{"level": "info"},
+	}
+	src := map[string]interface{}{
+		"tags": []interface{}{"c"},
+		"database": map[string]interface{}{
+			"host": "db.example.com",
+			"pool": map[string]interface{}{"max": 20},
+		},
+		"logging": "debug",
+		"debug":   true,
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name": "base",
+		"tags": []interface{}{"c"},
+		"database": map[string]interface{}{
+			"host": "db.example.com",
+			"port": 5432,
+			"pool": map[string]interface{}{"min": 1, "max": 20},
+		},
+		"logging": "debug",
+		"debug":   true,
+	}, DeepMerge(dst, src))
+
+	assert.Equal(t, "localhost", dst["database"].(map[string]interface{})["host"])
+	assert.Equal(t, map[string]interface{}{"level": "info"}, dst["logging"])
+
+	assert.Equal(t, map[string]interface{}{"logging": map[string]interface{}{"level": "info"}},
+		DeepMerge(map[string]interface{}{"logging": "debug"}, map[string]interface{}{"logging": map[string]interface{}{"level": "info"}}))
+	assert.Equal(t, map[string]interface{}{}, DeepMerge(nil, nil))
+	assert.Equal(t, map[string]interface{}{"a": 1}, DeepMerge(nil, map[string]interface{}{"a": 1}))
+}
+
+func TestRetry(t *testing.T) {
+	calls := 0
+	err := Retry(3, time.Millisecond, func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	start := time.Now()
+	err = Retry(3, 10*time.Millisecond, func() error {
+		calls++
+		return fmt.Errorf("attempt %d failed", calls)
+	})
+	assert.EqualError(t, err, "attempt 3 failed")
+	assert.Equal(t, 3, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	calls = 0
+	err = Retry(0, time.Millisecond, func() error {
+		calls++
+		return nil
+	})
+	assert.ErrorContains(t, err, "invalid number of attempts 0")
+	assert.Equal(t, 0, calls)
+
+	err = RetryContext(context.Background(), -1, time.Millisecond, func() error {
+		calls++
+		return nil
+	})
+	assert.ErrorContains(t, err, "invalid number of attempts -1")
+	assert.Equal(t, 0, calls)
+}
+
+func TestRetryContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := RetryContext(ctx, 5, time.Hour, func() error {
+		calls++
+		cancel()
+		return errors.New("unavailable")
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "unavailable")
+	assert.Equal(t, 1, calls)
+}
+
+func TestDebounce(t *testing.T) {
+	var calls int32
+	debounced := Debounce(50*time.Millisecond, func() {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	for i := 0; i < 5; i++ {
+		debounced()
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	debounced()
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestSet(t *testing.T) {
+	var s Set[string]
+	assert.Equal(t, 0, s.Len())
+	assert.False(t, s.Has("a"))
+	assert.False(t, s.Remove("a"))
+	assert.Equal(t, []string{}, s.Slice())
+
+	assert.True(t, s.Add("b"))
+	assert.True(t, s.Add("a"))
+	assert.True(t, s.Add("c"))
+	assert.False(t, s.Add("a"))
+	assert.True(t, s.Has("a"))
+	assert.Equal(t, 3, s.Len())
+	assert.Equal(t, []string{"b", "a", "c"}, s.Slice())
+
+	assert.True(t, s.Remove("b"))
+	assert.False(t, s.Has("b"))
+	assert.True(t, s.Has("c"))
+	assert.Equal(t, 2, s.Len())
+	assert.Equal(t, []string{"a", "c"}, s.Slice())
+
+	assert.True(t, s.Remove("c"))
+	assert.True(t, s.Add("b"))
+	assert.Equal(t, []string{"a", "b"}, s.Slice())
+
+	slice := s.Slice()
+	slice[0] = "changed"
+	assert.Equal(t, []string{"a", "b"}, s.Slice())
+
+	ints := NewSet(3, 1, 3, 2, 1)
+	assert.Equal(t, 3, ints.Len())
+	assert.Equal(t, []int{3, 1, 2}, ints.Slice())
+}
@@ -0,0 +1,114 @@
+package libstandard
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// checkValidation applies the rules of the validate-tags to the read values.
+// Supported rules are "min=<n>" and "max=<n>", which bound numbers by their value and strings,
+// slices and maps by their length, "dive", which applies the following rules to each element
+// of a slice or array, and "required_if=<Field> <value>", which requires a value if the sibling
+// field with the given name holds the given value, e.g. "required_if=TLSEnabled true".
+func checkValidation(metaInfo []structMeta) error {
+	fields := make(map[string]reflect.Value, len(metaInfo))
+	for _, meta := range metaInfo {
+		fields[meta.fieldPath] = meta.fieldValue
+	}
+
+	for _, meta := range metaInfo {
+		if meta.validate == "" {
+			continue
+		}
+
+		parent := strings.TrimSuffix(meta.fieldPath, meta.fieldName)
+		sibling := func(name string) (reflect.Value, bool) {
+			value, ok := fields[parent+name]
+			return value, ok
+		}
+
+		if err := validateValue(meta.fieldValue, strings.Split(meta.validate, DefaultSeparator), sibling); err != nil {
+			return fmt.Errorf("field %q is invalid: %w", meta.fieldName, err)
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks the value against the rules, descending into elements on "dive".
+// sibling looks up the fields referenced by conditional rules.
+func validateValue(value reflect.Value, rules []string, sibling func(string) (reflect.Value, bool)) error {
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch name {
+		case "dive":
+			if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+				return fmt.Errorf("dive requires a slice or array, got %s", value.Type())
+			}
+
+			for idx := 0; idx < value.Len(); idx++ {
+				if err := validateValue(value.Index(idx), rules[i+1:], sibling); err != nil {
+					return fmt.Errorf("element %d: %w", idx, err)
+				}
+			}
+			return nil
+
+		case "min", "max":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s rule %q: %w", name, rule, err)
+			}
+
+			actual, unit, err := measure(value)
+			if err != nil {
+				return err
+			}
+
+			if name == "min" && actual < limit {
+				return fmt.Errorf("%s %v is less than min %s", unit, actual, param)
+			}
+			if name == "max" && actual > limit {
+				return fmt.Errorf("%s %v is greater than max %s", unit, actual, param)
+			}
+
+		case "required_if":
+			fieldName, expected, ok := strings.Cut(strings.TrimSpace(param), " ")
+			if !ok {
+				return fmt.Errorf("invalid %s rule %q: expected field name and value", name, rule)
+			}
+
+			field, ok := sibling(fieldName)
+			if !ok {
+				return fmt.Errorf("invalid %s rule %q: unknown field %q", name, rule, fieldName)
+			}
+
+			if fmt.Sprint(field.Interface()) == strings.TrimSpace(expected) && isZero(value) {
+				return fmt.Errorf("value is required if %s is %s", fieldName, strings.TrimSpace(expected))
+			}
+
+		default:
+			return fmt.Errorf("unknown validation rule %q", rule)
+		}
+	}
+
+	return nil
+}
+
+// measure returns the quantity min- and max-rules are compared against, along with its description
+func measure(value reflect.Value) (float64, string, error) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "value", nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), "value", nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "value", nil
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), "length", nil
+	default:
+		return 0, "", fmt.Errorf("min and max are not supported for %s", value.Type())
+	}
+}
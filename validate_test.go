@@ -0,0 +1,151 @@
+package libstandard
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDive(t *testing.T) {
+	type config struct {
+		Ports []int  `env:"PORTS" validate:"dive,min=1,max=65535"`
+		IDs   []uint `env:"IDS" validate:"min=1,dive,max=10"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    config
+		wantErr string
+	}{
+		{
+			name: "valid ports",
+			env:  map[string]string{"PORTS": "1,8080,65535", "IDS": "3"},
+			want: config{Ports: []int{1, 8080, 65535}, IDs: []uint{3}},
+		},
+		{
+			name:    "port out of range",
+			env:     map[string]string{"PORTS": "80,70000", "IDS": "3"},
+			wantErr: `field "Ports" is invalid: element 1: value 70000 is greater than max 65535`,
+		},
+		{
+			name:    "port below min",
+			env:     map[string]string{"PORTS": "0", "IDS": "3"},
+			wantErr: `field "Ports" is invalid: element 0: value 0 is less than min 1`,
+		},
+		{
+			name:    "slice too short",
+			env:     map[string]string{"PORTS": "80"},
+			wantErr: `field "IDs" is invalid: length 0 is less than min 1`,
+		},
+		{
+			name:    "uint out of range",
+			env:     map[string]string{"PORTS": "80", "IDS": "1,2,11"},
+			wantErr: `field "IDs" is invalid: element 2: value 11 is greater than max 10`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := Read(&cfg, nil, "", DefaultFileConfig{})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestValidateRequiredIf(t *testing.T) {
+	type tls struct {
+		Enabled bool   `env:"TLS_ENABLED"`
+		Cert    string `env:"TLS_CERT" validate:"required_if=Enabled true"`
+	}
+	type config struct {
+		Mode   string `env:"MODE"`
+		Socket string `env:"SOCKET" validate:"required_if=Mode unix"`
+		TLS    tls
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    config
+		wantErr string
+	}{
+		{
+			name: "conditions not met",
+			env:  map[string]string{"MODE": "tcp"},
+			want: config{Mode: "tcp"},
+		},
+		{
+			name: "conditions met and values given",
+			env:  map[string]string{"MODE": "unix", "SOCKET": "/run/app.sock", "TLS_ENABLED": "true", "TLS_CERT": "cert.pem"},
+			want: config{Mode: "unix", Socket: "/run/app.sock", TLS: tls{Enabled: true, Cert: "cert.pem"}},
+		},
+		{
+			name:    "string condition met",
+			env:     map[string]string{"MODE": "unix"},
+			wantErr: `field "Socket" is invalid: value is required if Mode is unix`,
+		},
+		{
+			name:    "bool condition of nested sibling met",
+			env:     map[string]string{"TLS_ENABLED": "true"},
+			wantErr: `field "Cert" is invalid: value is required if Enabled is true`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := Read(&cfg, nil, "", DefaultFileConfig{})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestValidateInvalidRules(t *testing.T) {
+	type unknownRule struct {
+		Port int `validate:"positive"`
+	}
+	type diveScalar struct {
+		Port int `validate:"dive,min=1"`
+	}
+	type invalidLimit struct {
+		Port int `validate:"max=abc"`
+	}
+	type unknownSibling struct {
+		Cert string `validate:"required_if=TLSEnabled true"`
+	}
+	type missingValue struct {
+		Enabled bool
+		Cert    string `validate:"required_if=Enabled"`
+	}
+
+	assert.Error(t, Read(&unknownRule{}, nil, "", DefaultFileConfig{}))
+	assert.Error(t, Read(&diveScalar{}, nil, "", DefaultFileConfig{}))
+	assert.Error(t, Read(&invalidLimit{}, nil, "", DefaultFileConfig{}))
+	assert.Error(t, Read(&unknownSibling{}, nil, "", DefaultFileConfig{}))
+	assert.Error(t, Read(&missingValue{}, nil, "", DefaultFileConfig{}))
+}
@@ -0,0 +1,116 @@
+package libstandard
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// watchDebounce is the quiet period after the last file-event before the change-callback is invoked.
+var watchDebounce = 100 * time.Millisecond
+
+// Watch watches the given config-file and invokes onChange after it was written or replaced.
+// The parent directory is watched instead of the file itself, so atomic saves (write to a temp-file
+// and rename it) and files which are temporarily removed and recreated are picked up as well.
+// Rapid successive events (e.g. from editors) are debounced into a single call of onChange.
+// Errors returned by onChange are logged. The returned stop function tears down the watcher, it may also be
+// called from onChange, e.g. after a successful reload. Called while onChange runs, it returns without waiting
+// for onChange to finish, otherwise it waits until no call of onChange is in progress anymore.
+//
+// Example:
+//
+//	 stop, err := config.Watch("config.yml", func() error {
+//	     return config.Reload(&cfg, cmd.Flags(), "config.yml", DefaultFileConfig{})
+//	 })
+//	 if err != nil {
+//	     ...
+//	 }
+//	 defer stop()
+func Watch(file string, onChange func() error) (func(), error) {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	err = watcher.Add(filepath.Dir(path))
+	if err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	changed := make(chan struct{})
+	debounced := Debounce(watchDebounce, func() {
+		select {
+		case changed <- struct{}{}:
+		case <-done:
+		}
+	})
+
+	var wg sync.WaitGroup
+	var inCallback atomic.Bool
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-done:
+				return
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if event.Name != path || !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+					continue
+				}
+
+				debounced()
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				logrus.WithError(err).Warnf("Error while watching config-file %s", path)
+
+			case <-changed:
+				inCallback.Store(true)
+				err := onChange()
+				inCallback.Store(false)
+
+				if err != nil {
+					logrus.WithError(err).Errorf("Could not apply changes of config-file %s", path)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+		})
+
+		// waiting for the goroutine would deadlock if onChange stops the watcher itself,
+		// the goroutine closes the watcher anyway after onChange returned
+		if !inCallback.Load() {
+			wg.Wait()
+		}
+	}
+
+	return stop, nil
+}
@@ -0,0 +1,103 @@
+package libstandard
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	err := os.WriteFile(file, []byte("one: 1"), 0600)
+	assert.NoError(t, err)
+
+	var calls int32
+	stop, err := Watch(file, func() error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		err = os.WriteFile(file, []byte("one: 2"), 0600)
+		assert.NoError(t, err)
+	}
+
+	time.Sleep(watchDebounce * 5)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	err = os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("one: 3"), 0600)
+	assert.NoError(t, err)
+
+	time.Sleep(watchDebounce * 5)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	tmp := filepath.Join(dir, "config.yaml.tmp")
+	err = os.WriteFile(tmp, []byte("one: 4"), 0600)
+	assert.NoError(t, err)
+	err = os.Rename(tmp, file)
+	assert.NoError(t, err)
+
+	time.Sleep(watchDebounce * 5)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	err = os.Remove(file)
+	assert.NoError(t, err)
+	err = os.WriteFile(file, []byte("one: 5"), 0600)
+	assert.NoError(t, err)
+
+	time.Sleep(watchDebounce * 5)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	stop()
+	stop()
+
+	err = os.WriteFile(file, []byte("one: 6"), 0600)
+	assert.NoError(t, err)
+
+	time.Sleep(watchDebounce * 5)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestWatchStopInCallback(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte("one: 1"), 0600)
+	assert.NoError(t, err)
+
+	var calls int32
+	returned := make(chan struct{})
+	var stop func()
+	stop, err = Watch(file, func() error {
+		atomic.AddInt32(&calls, 1)
+		stop()
+		close(returned)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = os.WriteFile(file, []byte("one: 2"), 0600)
+	assert.NoError(t, err)
+
+	select {
+	case <-returned:
+	case <-time.After(watchDebounce * 20):
+		t.Fatal("stop did not return when called from onChange")
+	}
+
+	stop()
+	err = os.WriteFile(file, []byte("one: 3"), 0600)
+	assert.NoError(t, err)
+
+	time.Sleep(watchDebounce * 5)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestWatchInvalidPath(t *testing.T) {
+	_, err := Watch(filepath.Join(t.TempDir(), "missing", "config.yaml"), func() error { return nil })
+	assert.Error(t, err)
+}